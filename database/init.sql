@@ -1,13 +1,191 @@
 CREATE TABLE cliente (
     id TEXT PRIMARY KEY,
     nombre TEXT NOT NULL,
-    telefono TEXT NOT NULL,
-    preferenciahoraria TEXT NOT NULL
+    telefono TEXT NOT NULL UNIQUE,
+    preferenciahoraria TEXT NOT NULL,
+    restriccion TEXT NOT NULL DEFAULT 'ninguna'
+        CHECK (restriccion IN ('ninguna', 'seña', 'bloqueado')),
+    restriccion_levantada_en TIMESTAMPTZ,
+    anonimizado_en TIMESTAMPTZ -- NULL mientras conserve sus datos personales
+);
+
+-- Búsqueda de clientes por nombre sin acentos y por parte del teléfono.
+-- unaccent no es IMMUTABLE, así que se envuelve para poder indexarla.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE FUNCTION sin_acentos(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent', $1) $$;
+
+CREATE INDEX cliente_nombre_trgm_idx ON cliente USING gin (sin_acentos(lower(nombre)) gin_trgm_ops);
+CREATE INDEX cliente_telefono_trgm_idx ON cliente USING gin (telefono gin_trgm_ops);
+
+CREATE TABLE serie (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id),
+    rrule TEXT NOT NULL,
+    fecha_inicio DATE NOT NULL,
+    creada_en TIMESTAMPTZ NOT NULL DEFAULT now()
 );
 
 CREATE TABLE turno (
     id TEXT PRIMARY KEY,
+    inicio TIMESTAMPTZ NOT NULL,
+    duracion INTEGER NOT NULL DEFAULT 30, -- minutos
+    cliente_id TEXT NOT NULL REFERENCES cliente(id),
+    estado TEXT NOT NULL DEFAULT 'pendiente'
+        CHECK (estado IN ('pendiente', 'confirmado', 'cancelado', 'completado', 'ausente')),
+    cancelado_por TEXT CHECK (cancelado_por IN ('cliente', 'negocio')),
+    motivo_cancelacion TEXT,
+    cancelacion_tardia BOOLEAN NOT NULL DEFAULT FALSE,
+    cancelado_en TIMESTAMPTZ,
+    sena NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    serie_id TEXT REFERENCES serie(id)
+);
+
+CREATE INDEX turno_cliente_idx ON turno (cliente_id);
+CREATE INDEX turno_serie_idx ON turno (serie_id);
+
+CREATE INDEX turno_inicio_idx ON turno (inicio);
+
+CREATE TABLE turno_historial (
+    id SERIAL PRIMARY KEY,
+    turno_id TEXT NOT NULL REFERENCES turno(id) ON DELETE CASCADE,
+    estado_anterior TEXT NOT NULL,
+    estado TEXT NOT NULL,
+    fecha TIMESTAMPTZ NOT NULL,
+    detalle TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX turno_historial_turno_idx ON turno_historial (turno_id);
+
+CREATE TABLE servicio (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL,
+    duracion INTEGER NOT NULL, -- minutos
+    precio NUMERIC(10, 2) NOT NULL,
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    -- preparación y limpieza en minutos; NULL usa el margen general
+    margen_antes INTEGER CHECK (margen_antes BETWEEN 0 AND 60),
+    margen_despues INTEGER CHECK (margen_despues BETWEEN 0 AND 60),
+    -- minutos de cada fase, alternando trabajo y pose (empieza con trabajo);
+    -- NULL es todo trabajo
+    fases INTEGER[]
+);
+
+-- precio, duracion, márgenes y fases guardan los valores del servicio al
+-- momento de reservar, para que un cambio en el catálogo no altere los turnos
+-- ya tomados
+CREATE TABLE turno_servicio (
+    turno_id TEXT NOT NULL REFERENCES turno(id) ON DELETE CASCADE,
+    servicio_id TEXT NOT NULL REFERENCES servicio(id),
+    orden INTEGER NOT NULL,
+    precio NUMERIC(10, 2) NOT NULL,
+    duracion INTEGER NOT NULL, -- minutos
+    margen_antes INTEGER,
+    margen_despues INTEGER,
+    fases INTEGER[],
+    PRIMARY KEY (turno_id, servicio_id)
+);
+
+-- Franjas de atención por día de la semana; un día sin filas está cerrado.
+CREATE TABLE horario_laboral (
+    dia_semana SMALLINT NOT NULL CHECK (dia_semana BETWEEN 0 AND 6), -- 0 = domingo
+    desde TEXT NOT NULL,
+    hasta TEXT NOT NULL,
+    PRIMARY KEY (dia_semana, desde)
+);
+
+-- Horario inicial: lunes a sábado de 9 a 19. Sin filas todos los días
+-- quedarían cerrados y no se podría reservar nada.
+INSERT INTO horario_laboral (dia_semana, desde, hasta)
+SELECT dia, '09:00', '19:00' FROM generate_series(1, 6) AS dia;
+
+CREATE TABLE lista_espera (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id),
+    desde DATE NOT NULL,
+    hasta DATE NOT NULL,
+    preferencia SMALLINT, -- NULL: cualquier horario
+    duracion INTEGER NOT NULL, -- minutos
+    servicio_ids TEXT[] NOT NULL DEFAULT '{}',
+    estado TEXT NOT NULL DEFAULT 'esperando'
+        CHECK (estado IN ('esperando', 'con oferta', 'atendida', 'retirada')),
+    creada_en TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (desde <= hasta)
+);
+
+CREATE INDEX lista_espera_estado_idx ON lista_espera (estado, creada_en);
+
+CREATE TABLE oferta_espera (
+    id TEXT PRIMARY KEY,
+    entrada_id TEXT NOT NULL REFERENCES lista_espera(id) ON DELETE CASCADE,
     fecha DATE NOT NULL,
     hora TEXT NOT NULL,
-    cliente_id TEXT NOT NULL REFERENCES cliente(id)
+    duracion INTEGER NOT NULL, -- minutos del lugar liberado
+    vence_en TIMESTAMPTZ NOT NULL,
+    estado TEXT NOT NULL DEFAULT 'pendiente'
+        CHECK (estado IN ('pendiente', 'aceptada', 'rechazada', 'vencida')),
+    turno_id TEXT REFERENCES turno(id) ON DELETE SET NULL,
+    creada_en TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX oferta_espera_pendiente_idx ON oferta_espera (vence_en) WHERE estado = 'pendiente';
+
+CREATE TABLE bloqueo (
+    id TEXT PRIMARY KEY,
+    fecha DATE NOT NULL,
+    desde TEXT NOT NULL,
+    hasta TEXT NOT NULL,
+    motivo TEXT NOT NULL,
+    semanal BOOLEAN NOT NULL DEFAULT FALSE,
+    fin_repeticion DATE, -- solo para los semanales; NULL: sin fin
+    CHECK (desde < hasta)
+);
+
+CREATE INDEX bloqueo_fecha_idx ON bloqueo (fecha);
+
+-- Avisos a clientes pendientes de envío; enviada_en queda NULL hasta que se despachan.
+CREATE TABLE notificacion (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id) ON DELETE CASCADE,
+    turno_id TEXT REFERENCES turno(id) ON DELETE SET NULL,
+    telefono TEXT NOT NULL,
+    mensaje TEXT NOT NULL,
+    creada_en TIMESTAMPTZ NOT NULL,
+    enviada_en TIMESTAMPTZ
+);
+
+CREATE INDEX notificacion_pendiente_idx ON notificacion (creada_en) WHERE enviada_en IS NULL;
+
+-- Registro de operaciones sobre los datos de clientes (fusiones de
+-- duplicados, exportaciones y anonimizaciones). cliente_id no referencia a
+-- cliente para que la entrada quede aunque el cliente se borre.
+CREATE TABLE auditoria_cliente (
+    id TEXT PRIMARY KEY,
+    accion TEXT NOT NULL,
+    cliente_id TEXT NOT NULL,
+    detalle JSONB NOT NULL,
+    realizada_en TIMESTAMPTZ NOT NULL
 );
+
+CREATE INDEX auditoria_cliente_idx ON auditoria_cliente (cliente_id, realizada_en);
+
+-- Ficha técnica: lo que se le hizo a cada cliente (tintura, fórmula, oxidante,
+-- pose) y datos a tener en cuenta como el tipo de cabello o sus alergias.
+CREATE TABLE ficha (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id),
+    turno_id TEXT REFERENCES turno(id) ON DELETE SET NULL,
+    fecha DATE NOT NULL,
+    marca TEXT NOT NULL DEFAULT '',
+    formula TEXT NOT NULL DEFAULT '',
+    oxidante SMALLINT NOT NULL DEFAULT 0, -- volúmenes
+    tiempo_pose INTEGER NOT NULL DEFAULT 0, -- minutos
+    tipo_cabello TEXT NOT NULL DEFAULT '',
+    alergias TEXT NOT NULL DEFAULT '',
+    notas TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ficha_cliente_idx ON ficha (cliente_id, fecha);
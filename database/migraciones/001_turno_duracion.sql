@@ -0,0 +1,5 @@
+-- Duración de cada turno, para detectar superposiciones. Los turnos ya
+-- cargados quedan con los 30 minutos que se asumían hasta ahora.
+ALTER TABLE turno ADD COLUMN duracion INTEGER NOT NULL DEFAULT 30; -- minutos
+
+CREATE INDEX turno_fecha_idx ON turno (fecha);
@@ -0,0 +1,18 @@
+-- Catálogo de servicios y los servicios de cada turno.
+CREATE TABLE servicio (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL,
+    duracion INTEGER NOT NULL, -- minutos
+    precio NUMERIC(10, 2) NOT NULL,
+    activo BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+-- precio guarda el valor cobrado al momento de reservar, para que un cambio
+-- de precio en el catálogo no altere los turnos ya tomados
+CREATE TABLE turno_servicio (
+    turno_id TEXT NOT NULL REFERENCES turno(id) ON DELETE CASCADE,
+    servicio_id TEXT NOT NULL REFERENCES servicio(id),
+    orden INTEGER NOT NULL,
+    precio NUMERIC(10, 2) NOT NULL,
+    PRIMARY KEY (turno_id, servicio_id)
+);
@@ -0,0 +1,12 @@
+-- Franjas de atención por día de la semana.
+CREATE TABLE horario_laboral (
+    dia_semana SMALLINT NOT NULL CHECK (dia_semana BETWEEN 0 AND 6), -- 0 = domingo
+    desde TEXT NOT NULL,
+    hasta TEXT NOT NULL,
+    PRIMARY KEY (dia_semana, desde)
+);
+
+-- Horario inicial: lunes a sábado de 9 a 19. Sin filas todos los días
+-- quedarían cerrados y no se podría reservar nada.
+INSERT INTO horario_laboral (dia_semana, desde, hasta)
+SELECT dia, '09:00', '19:00' FROM generate_series(1, 6) AS dia;
@@ -0,0 +1,15 @@
+-- Estado de cada turno y el registro de sus cambios. Los turnos ya cargados
+-- quedan pendientes.
+ALTER TABLE turno ADD COLUMN estado TEXT NOT NULL DEFAULT 'pendiente'
+    CHECK (estado IN ('pendiente', 'confirmado', 'cancelado', 'completado', 'ausente'));
+
+CREATE TABLE turno_historial (
+    id SERIAL PRIMARY KEY,
+    turno_id TEXT NOT NULL REFERENCES turno(id) ON DELETE CASCADE,
+    estado_anterior TEXT NOT NULL,
+    estado TEXT NOT NULL,
+    fecha TIMESTAMPTZ NOT NULL,
+    detalle TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX turno_historial_turno_idx ON turno_historial (turno_id);
@@ -0,0 +1,6 @@
+-- Quién canceló cada turno, cuándo y por qué.
+ALTER TABLE turno
+    ADD COLUMN cancelado_por TEXT CHECK (cancelado_por IN ('cliente', 'negocio')),
+    ADD COLUMN motivo_cancelacion TEXT,
+    ADD COLUMN cancelacion_tardia BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN cancelado_en TIMESTAMPTZ;
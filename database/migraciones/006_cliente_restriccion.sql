@@ -0,0 +1,9 @@
+-- Restricción de los clientes que faltan seguido y la seña de cada turno.
+ALTER TABLE cliente
+    ADD COLUMN restriccion TEXT NOT NULL DEFAULT 'ninguna'
+        CHECK (restriccion IN ('ninguna', 'seña', 'bloqueado')),
+    ADD COLUMN restriccion_levantada_en TIMESTAMPTZ;
+
+ALTER TABLE turno ADD COLUMN sena NUMERIC(10, 2) NOT NULL DEFAULT 0;
+
+CREATE INDEX turno_cliente_idx ON turno (cliente_id);
@@ -0,0 +1,12 @@
+-- Series de turnos que se repiten.
+CREATE TABLE serie (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id),
+    rrule TEXT NOT NULL,
+    fecha_inicio DATE NOT NULL,
+    creada_en TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE turno ADD COLUMN serie_id TEXT REFERENCES serie(id);
+
+CREATE INDEX turno_serie_idx ON turno (serie_id);
@@ -0,0 +1,31 @@
+-- Lista de espera y los lugares liberados que se le ofrecen.
+CREATE TABLE lista_espera (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id),
+    desde DATE NOT NULL,
+    hasta DATE NOT NULL,
+    preferencia SMALLINT, -- NULL: cualquier horario
+    duracion INTEGER NOT NULL, -- minutos
+    servicio_ids TEXT[] NOT NULL DEFAULT '{}',
+    estado TEXT NOT NULL DEFAULT 'esperando'
+        CHECK (estado IN ('esperando', 'con oferta', 'atendida', 'retirada')),
+    creada_en TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (desde <= hasta)
+);
+
+CREATE INDEX lista_espera_estado_idx ON lista_espera (estado, creada_en);
+
+CREATE TABLE oferta_espera (
+    id TEXT PRIMARY KEY,
+    entrada_id TEXT NOT NULL REFERENCES lista_espera(id) ON DELETE CASCADE,
+    fecha DATE NOT NULL,
+    hora TEXT NOT NULL,
+    duracion INTEGER NOT NULL, -- minutos del lugar liberado
+    vence_en TIMESTAMPTZ NOT NULL,
+    estado TEXT NOT NULL DEFAULT 'pendiente'
+        CHECK (estado IN ('pendiente', 'aceptada', 'rechazada', 'vencida')),
+    turno_id TEXT REFERENCES turno(id) ON DELETE SET NULL,
+    creada_en TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX oferta_espera_pendiente_idx ON oferta_espera (vence_en) WHERE estado = 'pendiente';
@@ -0,0 +1,13 @@
+-- Franjas de la agenda en las que no se atiende.
+CREATE TABLE bloqueo (
+    id TEXT PRIMARY KEY,
+    fecha DATE NOT NULL,
+    desde TEXT NOT NULL,
+    hasta TEXT NOT NULL,
+    motivo TEXT NOT NULL,
+    semanal BOOLEAN NOT NULL DEFAULT FALSE,
+    fin_repeticion DATE, -- solo para los semanales; NULL: sin fin
+    CHECK (desde < hasta)
+);
+
+CREATE INDEX bloqueo_fecha_idx ON bloqueo (fecha);
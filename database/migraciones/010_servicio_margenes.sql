@@ -0,0 +1,4 @@
+-- Preparación y limpieza de cada servicio en minutos; NULL usa el margen general.
+ALTER TABLE servicio
+    ADD COLUMN margen_antes INTEGER CHECK (margen_antes BETWEEN 0 AND 60),
+    ADD COLUMN margen_despues INTEGER CHECK (margen_despues BETWEEN 0 AND 60);
@@ -0,0 +1,24 @@
+-- Reemplaza fecha (DATE) + hora (TEXT) de cada turno por un único instante.
+-- Las horas guardadas hasta ahora son horas locales del negocio, así que la
+-- migración necesita la misma zona que ZONA_HORARIA:
+--   psql -v zona_horaria="$ZONA_HORARIA" -f 011_turno_inicio.sql
+\if :{?zona_horaria}
+\else
+  \echo 'falta la zona: correr con -v zona_horaria="$ZONA_HORARIA"'
+  \quit
+\endif
+
+BEGIN;
+
+ALTER TABLE turno ADD COLUMN inicio TIMESTAMPTZ;
+
+UPDATE turno
+SET inicio = (fecha + hora::time) AT TIME ZONE :'zona_horaria';
+
+ALTER TABLE turno ALTER COLUMN inicio SET NOT NULL;
+
+DROP INDEX IF EXISTS turno_fecha_idx;
+ALTER TABLE turno DROP COLUMN fecha, DROP COLUMN hora;
+CREATE INDEX turno_inicio_idx ON turno (inicio);
+
+COMMIT;
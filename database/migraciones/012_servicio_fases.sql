@@ -0,0 +1,2 @@
+-- Fases de trabajo y pose de cada servicio.
+ALTER TABLE servicio ADD COLUMN fases INTEGER[];
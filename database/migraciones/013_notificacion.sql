@@ -0,0 +1,12 @@
+-- Avisos a clientes pendientes de envío.
+CREATE TABLE notificacion (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id) ON DELETE CASCADE,
+    turno_id TEXT REFERENCES turno(id) ON DELETE SET NULL,
+    telefono TEXT NOT NULL,
+    mensaje TEXT NOT NULL,
+    creada_en TIMESTAMPTZ NOT NULL,
+    enviada_en TIMESTAMPTZ
+);
+
+CREATE INDEX notificacion_pendiente_idx ON notificacion (creada_en) WHERE enviada_en IS NULL;
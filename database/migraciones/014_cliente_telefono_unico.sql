@@ -0,0 +1,140 @@
+-- Un teléfono identifica a un solo cliente. Antes de agregar la restricción
+-- los teléfonos ya cargados se pasan a formato E.164 con las mismas reglas que
+-- la aplicación (domain.NormalizarTelefono), así que hace falta el mismo
+-- código de área que CODIGO_AREA:
+--   psql -v codigo_area="$CODIGO_AREA" -f 014_cliente_telefono_unico.sql
+-- Si dos clientes quedan con el mismo teléfono, la migración los lista y no
+-- cambia nada. Se pueden unificar a mano y volver a correrla, o correrla con
+-- -v fusionar=1 para que pase todo al cliente con más turnos de cada grupo y
+-- borre los demás.
+\if :{?codigo_area}
+\else
+  \echo 'falta el código de área: correr con -v codigo_area="$CODIGO_AREA"'
+  \quit
+\endif
+\if :{?fusionar}
+\else
+  \set fusionar 0
+\endif
+
+BEGIN;
+
+-- Copia de domain.NormalizarTelefono: NULL si el teléfono no es válido.
+CREATE FUNCTION pg_temp.normalizar_telefono(tel TEXT, area TEXT) RETURNS TEXT
+    LANGUAGE plpgsql IMMUTABLE STRICT AS $$
+DECLARE
+    s TEXT := btrim(tel);
+    internacional BOOLEAN;
+    n INTEGER;
+BEGIN
+    IF s !~ '^\+?[0-9 ().-]*$' THEN
+        RETURN NULL;
+    END IF;
+    s := regexp_replace(s, '[ ().-]', '', 'g');
+    internacional := s LIKE '+%' OR s LIKE '00%';
+    s := regexp_replace(regexp_replace(s, '^\+', ''), '^00', '');
+    IF internacional THEN
+        IF s NOT LIKE '54%' THEN
+            IF length(s) BETWEEN 8 AND 15 THEN
+                RETURN '+' || s;
+            END IF;
+            RETURN NULL;
+        END IF;
+        s := regexp_replace(substr(s, 3), '^9', '');
+    END IF;
+
+    -- número nacional: área y número, con o sin 0 y 15
+    s := regexp_replace(s, '^0', '');
+    IF length(s) = 12 THEN
+        FOREACH n IN ARRAY ARRAY[2, 3, 4] LOOP
+            IF substr(s, n + 1, 2) = '15' AND (n <> 2 OR left(s, 2) = '11') THEN
+                RETURN '+549' || left(s, n) || substr(s, n + 3);
+            END IF;
+        END LOOP;
+        RETURN NULL;
+    ELSIF s LIKE '15%' AND length(area) + length(s) - 2 = 10 THEN
+        RETURN '+549' || area || substr(s, 3);
+    ELSIF length(s) = 10 THEN
+        RETURN '+549' || s;
+    ELSIF length(area) + length(s) = 10 THEN
+        RETURN '+549' || area || s;
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+-- Los teléfonos inválidos quedan como están; se corrigen al editar el cliente.
+CREATE TEMP TABLE telefono_nuevo ON COMMIT DROP AS
+SELECT c.id, c.nombre, c.telefono,
+       coalesce(pg_temp.normalizar_telefono(c.telefono, :'codigo_area'), c.telefono) AS normalizado,
+       (SELECT count(*) FROM turno t WHERE t.cliente_id = c.id) AS turnos
+FROM cliente c;
+
+\echo 'Teléfonos que no se pudieron normalizar:'
+SELECT id, nombre, telefono
+FROM telefono_nuevo
+WHERE pg_temp.normalizar_telefono(telefono, :'codigo_area') IS NULL
+ORDER BY nombre;
+
+-- Por cada teléfono repetido, el cliente que queda es el de más turnos.
+CREATE TEMP TABLE duplicado ON COMMIT DROP AS
+SELECT id, sobreviviente_id
+FROM (
+    SELECT id,
+           first_value(id) OVER grupo AS sobreviviente_id,
+           count(*) OVER (PARTITION BY normalizado) AS cantidad
+    FROM telefono_nuevo
+    WINDOW grupo AS (PARTITION BY normalizado ORDER BY turnos DESC, id)
+) g
+WHERE cantidad > 1 AND id <> sobreviviente_id;
+
+\echo 'Clientes con el mismo teléfono:'
+SELECT n.normalizado AS telefono,
+       string_agg(format('%s (%s, %s turnos)', n.nombre, n.id, n.turnos), '; '
+                  ORDER BY n.turnos DESC, n.id) AS clientes
+FROM telefono_nuevo n
+WHERE n.normalizado IN (
+    SELECT normalizado FROM telefono_nuevo GROUP BY normalizado HAVING count(*) > 1)
+GROUP BY n.normalizado
+ORDER BY n.normalizado;
+
+SELECT EXISTS (SELECT 1 FROM duplicado) AS hay_duplicados \gset
+
+\if :hay_duplicados
+  \if :fusionar
+    UPDATE turno t SET cliente_id = d.sobreviviente_id
+    FROM duplicado d WHERE t.cliente_id = d.id;
+    UPDATE serie s SET cliente_id = d.sobreviviente_id
+    FROM duplicado d WHERE s.cliente_id = d.id;
+    UPDATE lista_espera e SET cliente_id = d.sobreviviente_id
+    FROM duplicado d WHERE e.cliente_id = d.id;
+    UPDATE notificacion n SET cliente_id = d.sobreviviente_id
+    FROM duplicado d WHERE n.cliente_id = d.id;
+
+    -- el sobreviviente se queda con la restricción más dura del grupo
+    UPDATE cliente c SET restriccion = g.restriccion
+    FROM (
+        SELECT coalesce(d.sobreviviente_id, x.id) AS id,
+               (array_agg(x.restriccion ORDER BY array_position(
+                   ARRAY['bloqueado', 'seña', 'ninguna'], x.restriccion)))[1] AS restriccion
+        FROM cliente x
+        LEFT JOIN duplicado d ON d.id = x.id
+        GROUP BY 1
+    ) g
+    WHERE c.id = g.id AND c.restriccion <> g.restriccion;
+
+    DELETE FROM cliente c USING duplicado d WHERE c.id = d.id;
+  \else
+    \echo 'Hay clientes con el mismo teléfono: unificarlos o correr con -v fusionar=1. No se cambió nada.'
+    ROLLBACK;
+    \quit
+  \endif
+\endif
+
+UPDATE cliente c SET telefono = n.normalizado
+FROM telefono_nuevo n
+WHERE c.id = n.id AND c.telefono <> n.normalizado;
+
+ALTER TABLE cliente ADD CONSTRAINT cliente_telefono_key UNIQUE (telefono);
+
+COMMIT;
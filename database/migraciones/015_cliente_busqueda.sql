@@ -0,0 +1,11 @@
+-- Índices para la búsqueda de clientes por nombre sin acentos y por parte del teléfono.
+-- unaccent no es IMMUTABLE, así que se envuelve para poder indexarla.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE FUNCTION sin_acentos(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent', $1) $$;
+
+CREATE INDEX cliente_nombre_trgm_idx ON cliente USING gin (sin_acentos(lower(nombre)) gin_trgm_ops);
+CREATE INDEX cliente_telefono_trgm_idx ON cliente USING gin (telefono gin_trgm_ops);
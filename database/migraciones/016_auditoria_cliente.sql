@@ -0,0 +1,12 @@
+-- Registro de operaciones sobre los datos de clientes (fusiones de
+-- duplicados). cliente_id no referencia a cliente para que la entrada quede
+-- aunque el cliente se borre.
+CREATE TABLE auditoria_cliente (
+    id TEXT PRIMARY KEY,
+    accion TEXT NOT NULL,
+    cliente_id TEXT NOT NULL,
+    detalle JSONB NOT NULL,
+    realizada_en TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX auditoria_cliente_idx ON auditoria_cliente (cliente_id, realizada_en);
@@ -0,0 +1,17 @@
+-- Ficha técnica: lo que se le hizo a cada cliente (tintura, fórmula, oxidante,
+-- pose) y datos a tener en cuenta como el tipo de cabello o sus alergias.
+CREATE TABLE ficha (
+    id TEXT PRIMARY KEY,
+    cliente_id TEXT NOT NULL REFERENCES cliente(id),
+    turno_id TEXT REFERENCES turno(id) ON DELETE SET NULL,
+    fecha DATE NOT NULL,
+    marca TEXT NOT NULL DEFAULT '',
+    formula TEXT NOT NULL DEFAULT '',
+    oxidante SMALLINT NOT NULL DEFAULT 0, -- volúmenes
+    tiempo_pose INTEGER NOT NULL DEFAULT 0, -- minutos
+    tipo_cabello TEXT NOT NULL DEFAULT '',
+    alergias TEXT NOT NULL DEFAULT '',
+    notas TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ficha_cliente_idx ON ficha (cliente_id, fecha);
@@ -0,0 +1,2 @@
+-- Clientes que pidieron que se borren sus datos personales (Ley 25.326).
+ALTER TABLE cliente ADD COLUMN anonimizado_en TIMESTAMPTZ;
@@ -0,0 +1,22 @@
+-- Duración, márgenes y fases de cada servicio al momento de reservar, como ya
+-- se hacía con el precio: editar el catálogo no cambia los turnos tomados.
+-- Los turnos existentes toman los valores actuales del catálogo.
+BEGIN;
+
+ALTER TABLE turno_servicio
+    ADD COLUMN duracion INTEGER,
+    ADD COLUMN margen_antes INTEGER,
+    ADD COLUMN margen_despues INTEGER,
+    ADD COLUMN fases INTEGER[];
+
+UPDATE turno_servicio ts
+SET duracion = s.duracion,
+    margen_antes = s.margen_antes,
+    margen_despues = s.margen_despues,
+    fases = s.fases
+FROM servicio s
+WHERE s.id = ts.servicio_id;
+
+ALTER TABLE turno_servicio ALTER COLUMN duracion SET NOT NULL;
+
+COMMIT;
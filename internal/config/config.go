@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+// Config reúne las reglas del negocio que se pueden ajustar sin tocar el código.
+// Todos los valores salen de variables de entorno y tienen un valor por defecto.
+type Config struct {
+	// ZonaHoraria es la zona en la que se leen y se muestran las horas de los turnos.
+	ZonaHoraria *time.Location
+	// CodigoArea se agrega a los teléfonos que se cargan sin código de área.
+	CodigoArea  string
+	Reserva     domain.PoliticaReserva
+	Limite      domain.LimiteReservas
+	Cancelacion domain.PoliticaCancelacion
+	Margen      domain.Margen
+	Ausencias   domain.ReglaAusencias
+	// VigenciaOferta es cuánto tiempo tiene alguien de la lista de espera para aceptar un lugar.
+	VigenciaOferta time.Duration
+	// TokenAdmin identifica los pedidos del administrador, que pueden pasarse del
+	// límite de reservas. Vacío, nadie puede.
+	TokenAdmin string
+}
+
+func Load() (Config, error) {
+	var cfg Config
+	var err error
+
+	zona := texto("ZONA_HORARIA", "America/Argentina/Buenos_Aires")
+	if cfg.ZonaHoraria, err = time.LoadLocation(zona); err != nil {
+		return Config{}, fmt.Errorf("ZONA_HORARIA inválida: %w", err)
+	}
+	cfg.CodigoArea = texto("CODIGO_AREA", "11")
+	if strings.Trim(cfg.CodigoArea, "0123456789") != "" || len(cfg.CodigoArea) < 2 || len(cfg.CodigoArea) > 4 {
+		return Config{}, fmt.Errorf("CODIGO_AREA inválido: %q", cfg.CodigoArea)
+	}
+	// por defecto se reserva con al menos 2 horas y hasta 60 días de anticipación
+	if cfg.Reserva.AnticipacionMinima, err = duracion("RESERVA_ANTICIPACION_MINIMA", 2*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.Reserva.AnticipacionMaxima, err = duracion("RESERVA_ANTICIPACION_MAXIMA", 60*24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Reserva.Validate(); err != nil {
+		return Config{}, fmt.Errorf("RESERVA_ANTICIPACION_MINIMA o RESERVA_ANTICIPACION_MAXIMA: %w", err)
+	}
+
+	// por defecto cada cliente puede tener 4 turnos por venir, uno por semana
+	if cfg.Limite.Total, err = entero("LIMITE_RESERVAS_TOTAL", 4); err != nil {
+		return Config{}, err
+	}
+	if cfg.Limite.PorSemana, err = entero("LIMITE_RESERVAS_SEMANA", 1); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Limite.Validate(); err != nil {
+		return Config{}, fmt.Errorf("LIMITE_RESERVAS_TOTAL o LIMITE_RESERVAS_SEMANA: %w", err)
+	}
+
+	if cfg.Cancelacion.AnticipacionMinima, err = duracion("CANCELACION_ANTICIPACION_MINIMA", 12*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.Cancelacion.RechazarTardias, err = booleano("CANCELACION_RECHAZAR_TARDIAS", false); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Margen.Antes, err = duracion("MARGEN_ANTES", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.Margen.Despues, err = duracion("MARGEN_DESPUES", 0); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Margen.Validate(); err != nil {
+		return Config{}, fmt.Errorf("MARGEN_ANTES o MARGEN_DESPUES: %w", err)
+	}
+
+	// por defecto, 3 ausencias en 90 días bloquean al cliente
+	if cfg.Ausencias.Maximo, err = entero("AUSENCIAS_MAXIMO", 3); err != nil {
+		return Config{}, err
+	}
+	if cfg.Ausencias.Ventana, err = duracion("AUSENCIAS_VENTANA", 90*24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.Ausencias.ContarTardias, err = booleano("AUSENCIAS_CONTAR_TARDIAS", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.Ausencias.Accion, err = domain.ParseRestriccionCliente(texto("AUSENCIAS_ACCION", "bloqueado")); err != nil {
+		return Config{}, fmt.Errorf("AUSENCIAS_ACCION inválida: %w", err)
+	}
+	if cfg.Ausencias.SenaMinima, err = decimal("AUSENCIAS_SENA_MINIMA", 0); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.VigenciaOferta, err = duracion("ESPERA_VIGENCIA_OFERTA", 2*time.Hour); err != nil {
+		return Config{}, err
+	}
+	cfg.TokenAdmin = texto("ADMIN_TOKEN", "")
+	return cfg, nil
+}
+
+func duracion(clave string, porDefecto time.Duration) (time.Duration, error) {
+	v, ok := os.LookupEnv(clave)
+	if !ok {
+		return porDefecto, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválida: %w", clave, err)
+	}
+	return d, nil
+}
+
+func booleano(clave string, porDefecto bool) (bool, error) {
+	v, ok := os.LookupEnv(clave)
+	if !ok {
+		return porDefecto, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s inválida: %w", clave, err)
+	}
+	return b, nil
+}
+
+func entero(clave string, porDefecto int) (int, error) {
+	v, ok := os.LookupEnv(clave)
+	if !ok {
+		return porDefecto, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválida: %w", clave, err)
+	}
+	return n, nil
+}
+
+func decimal(clave string, porDefecto float64) (float64, error) {
+	v, ok := os.LookupEnv(clave)
+	if !ok {
+		return porDefecto, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s inválida: %w", clave, err)
+	}
+	return f, nil
+}
+
+func texto(clave, porDefecto string) string {
+	if v, ok := os.LookupEnv(clave); ok {
+		return v
+	}
+	return porDefecto
+}
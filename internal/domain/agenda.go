@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// DiaAgenda es un día de la agenda: sus turnos, con los datos del cliente, y
+// cuánto de lo que se atiende ese día ya está reservado.
+type DiaAgenda struct {
+	Fecha     time.Time
+	Turnos    []*Turno
+	Reservado time.Duration // suma de la duración de los turnos que ocupan lugar
+	Abierto   time.Duration // horario de atención menos los bloqueos
+}
+
+// Ocupacion es la fracción del tiempo abierto que está reservada; cero si el día
+// no se atiende.
+func (d DiaAgenda) Ocupacion() float64 {
+	if d.Abierto <= 0 {
+		return 0
+	}
+	return float64(d.Reservado) / float64(d.Abierto)
+}
+
+// Agenda agrupa por día los turnos entre Desde y Hasta, ambos inclusive.
+type Agenda struct {
+	Desde time.Time
+	Hasta time.Time
+	Dias  []DiaAgenda
+	// Formulas tiene, por ID de turno, la última fórmula de la ficha del
+	// cliente. Solo se cargan para los turnos por venir.
+	Formulas map[string]*EntradaFicha
+}
+
+// SemanaDe devuelve el lunes y el domingo de la semana de fecha.
+func SemanaDe(fecha time.Time) (desde, hasta time.Time) {
+	// Weekday cuenta desde el domingo; se corre para que la semana empiece el lunes
+	desde = fecha.AddDate(0, 0, -(int(fecha.Weekday())+6)%7)
+	return desde, desde.AddDate(0, 0, 6)
+}
+
+// MesDe devuelve el primer y el último día del mes de fecha.
+func MesDe(fecha time.Time) (desde, hasta time.Time) {
+	desde = time.Date(fecha.Year(), fecha.Month(), 1, 0, 0, 0, 0, fecha.Location())
+	return desde, desde.AddDate(0, 1, -1)
+}
+
+// MinutosAbiertos cuenta los minutos del día cubiertos por las franjas que no
+// caen en ninguno de los bloqueos.
+func MinutosAbiertos(fecha time.Time, franjas []IntervaloHorario, bloqueos []*Bloqueo) int {
+	var abierto [24 * 60]bool
+	for _, f := range franjas {
+		for m := f.Desde.Minutes(); m < f.Hasta.Minutes(); m++ {
+			abierto[m] = true
+		}
+	}
+	for _, b := range bloqueos {
+		if !b.AplicaEn(fecha) {
+			continue
+		}
+		for m := b.Desde.Minutes(); m < b.Hasta.Minutes(); m++ {
+			abierto[m] = false
+		}
+	}
+	total := 0
+	for _, a := range abierto {
+		if a {
+			total++
+		}
+	}
+	return total
+}
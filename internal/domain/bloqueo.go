@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrAgendaBloqueada se devuelve cuando un turno cae sobre un bloqueo de la agenda.
+var ErrAgendaBloqueada = errors.New("la agenda está bloqueada en ese horario")
+
+// Bloqueo reserva un rango horario de la agenda sin cliente: almuerzo, trámites,
+// un medio día libre. Si es semanal se repite el mismo día de la semana desde
+// Fecha hasta FinRepeticion (o sin fin, si es cero).
+type Bloqueo struct {
+	ID            string
+	Fecha         time.Time
+	Desde         TimeOfDay
+	Hasta         TimeOfDay
+	Motivo        string
+	Semanal       bool
+	FinRepeticion time.Time
+}
+
+func NewBloqueo(id string, fecha time.Time, desde, hasta TimeOfDay, motivo string, semanal bool, fin time.Time) *Bloqueo {
+	return &Bloqueo{
+		ID:            id,
+		Fecha:         fecha,
+		Desde:         desde,
+		Hasta:         hasta,
+		Motivo:        motivo,
+		Semanal:       semanal,
+		FinRepeticion: fin,
+	}
+}
+
+func (b *Bloqueo) Validate() error {
+	if b.Fecha.IsZero() {
+		return errors.New("fecha no puede ser cero")
+	}
+	if !b.Desde.IsBefore(b.Hasta) {
+		return errors.New("el bloqueo debe terminar después de empezar")
+	}
+	if b.Motivo == "" {
+		return errors.New("motivo requerido")
+	}
+	if !b.FinRepeticion.IsZero() && (!b.Semanal || b.FinRepeticion.Before(b.Fecha)) {
+		return errors.New("fin de repetición inválido")
+	}
+	return nil
+}
+
+// AplicaEn indica si el bloqueo ocupa parte del día fecha.
+func (b *Bloqueo) AplicaEn(fecha time.Time) bool {
+	if !b.Semanal {
+		return mismoDia(b.Fecha, fecha)
+	}
+	if fecha.Before(b.Fecha) && !mismoDia(b.Fecha, fecha) {
+		return false
+	}
+	if !b.FinRepeticion.IsZero() && fecha.After(b.FinRepeticion) && !mismoDia(b.FinRepeticion, fecha) {
+		return false
+	}
+	return fecha.Weekday() == b.Fecha.Weekday()
+}
+
+// SeSolapaCon indica si t ocupa al menos un minuto del bloqueo. Se mira el día
+// del turno y el siguiente, por si el turno pasa la medianoche.
+func (b *Bloqueo) SeSolapaCon(t *Turno) bool {
+	for _, dia := range []time.Time{t.Fecha, t.Fecha.AddDate(0, 0, 1)} {
+		if !b.AplicaEn(dia) {
+			continue
+		}
+		ocupado := &Turno{
+			Fecha:    dia,
+			Hora:     b.Desde,
+			Duracion: time.Duration(b.Hasta.Minutes()-b.Desde.Minutes()) * time.Minute,
+			Zona:     t.Zona,
+		}
+		if ocupado.SeSolapaCon(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// BloqueadoEntero indica si entre todos los bloqueos cubren el día fecha de
+// 00:00 a 24:00.
+func BloqueadoEntero(fecha time.Time, bloqueos []*Bloqueo) bool {
+	var rangos []*Bloqueo
+	for _, b := range bloqueos {
+		if b.AplicaEn(fecha) {
+			rangos = append(rangos, b)
+		}
+	}
+	slices.SortFunc(rangos, func(a, b *Bloqueo) int { return a.Desde.Minutes() - b.Desde.Minutes() })
+	cubierto := 0 // minutos del día cubiertos sin huecos desde las 00:00
+	for _, b := range rangos {
+		if b.Desde.Minutes() > cubierto {
+			return false
+		}
+		cubierto = max(cubierto, b.Hasta.Minutes())
+	}
+	return cubierto >= FinDelDia.Minutes()
+}
+
+// BloqueoError indica con qué bloqueo choca un turno.
+type BloqueoError struct {
+	Motivo string
+}
+
+func (e *BloqueoError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrAgendaBloqueada, e.Motivo)
+}
+
+func (e *BloqueoError) Unwrap() error {
+	return ErrAgendaBloqueada
+}
+
+func mismoDia(a, b time.Time) bool {
+	ya, ma, da := a.Date()
+	yb, mb, db := b.Date()
+	return ya == yb && ma == mb && da == db
+}
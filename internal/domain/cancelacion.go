@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OrigenCancelacion int
+
+const (
+	CanceladoPorCliente OrigenCancelacion = iota
+	CanceladoPorNegocio
+)
+
+func (o OrigenCancelacion) String() string {
+	return [...]string{"cliente", "negocio"}[o]
+}
+
+func ParseOrigenCancelacion(s string) (OrigenCancelacion, error) {
+	switch s {
+	case "cliente":
+		return CanceladoPorCliente, nil
+	case "negocio":
+		return CanceladoPorNegocio, nil
+	default:
+		return -1, fmt.Errorf("origen de cancelación no valido: %s", s)
+	}
+}
+
+var ErrCancelacionTardia = errors.New("la cancelación no respeta la anticipación mínima")
+
+// Cancelacion registra quién canceló un turno, por qué y si lo hizo con menos
+// anticipación de la que pide la política.
+type Cancelacion struct {
+	Origen OrigenCancelacion
+	Motivo string
+	Tardia bool
+	Fecha  time.Time
+}
+
+func (c *Cancelacion) Validate() error {
+	if c.Origen != CanceladoPorCliente && c.Origen != CanceladoPorNegocio {
+		return errors.New("origen de cancelación inválido")
+	}
+	if c.Motivo == "" {
+		return errors.New("motivo de cancelación requerido")
+	}
+	return nil
+}
+
+// Detalle resume la cancelación para el historial del turno.
+func (c *Cancelacion) Detalle() string {
+	detalle := fmt.Sprintf("cancelado por %s: %s", c.Origen, c.Motivo)
+	if c.Tardia {
+		detalle += " (cancelación tardía)"
+	}
+	return detalle
+}
+
+// PoliticaCancelacion define cuánta anticipación se le pide al cliente para
+// cancelar y qué pasa si no la respeta: se rechaza o se deja marcada como tardía.
+// Las cancelaciones del negocio no se limitan.
+type PoliticaCancelacion struct {
+	AnticipacionMinima time.Duration
+	RechazarTardias    bool
+}
+
+// EsTardia indica si cancelar ahora un turno que empieza en inicio queda dentro
+// de la anticipación mínima.
+func (p PoliticaCancelacion) EsTardia(inicio, ahora time.Time) bool {
+	return inicio.Sub(ahora) < p.AnticipacionMinima
+}
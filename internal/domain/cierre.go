@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DiasMaximosCierre acota cuántos días se pueden cerrar de una sola vez.
+const DiasMaximosCierre = 31
+
+// Cierre es un cierre de emergencia de la agenda (por ejemplo, por enfermedad):
+// los días de Desde a Hasta se bloquean enteros y sus turnos se cancelan.
+type Cierre struct {
+	Desde  time.Time
+	Hasta  time.Time
+	Motivo string
+}
+
+func (c Cierre) Validate() error {
+	if c.Desde.IsZero() || c.Hasta.IsZero() || c.Hasta.Before(c.Desde) {
+		return errors.New("rango de fechas inválido")
+	}
+	if c.Hasta.Sub(c.Desde) >= DiasMaximosCierre*24*time.Hour {
+		return fmt.Errorf("no se pueden cerrar más de %d días juntos", DiasMaximosCierre)
+	}
+	if c.Motivo == "" {
+		return errors.New("motivo requerido")
+	}
+	return nil
+}
+
+// Bloqueos devuelve un bloqueo de día completo, de 00:00 a 24:00, por cada día
+// del cierre que existentes no cubran ya enteros. Así cerrar dos veces los
+// mismos días no duplica los bloqueos.
+func (c Cierre) Bloqueos(existentes []*Bloqueo) []*Bloqueo {
+	var bloqueos []*Bloqueo
+	for dia := c.Desde; !dia.After(c.Hasta); dia = dia.AddDate(0, 0, 1) {
+		if BloqueadoEntero(dia, existentes) {
+			continue
+		}
+		bloqueos = append(bloqueos, NewBloqueo("", dia,
+			TimeOfDay{Hour: 0, Minute: 0}, FinDelDia, c.Motivo, false, time.Time{}))
+	}
+	return bloqueos
+}
+
+// Sugerencia es un horario libre que se le propone a un cliente para reemplazar
+// un turno cancelado.
+type Sugerencia struct {
+	Fecha  time.Time
+	Hora   TimeOfDay
+	Inicio time.Time // Fecha y Hora en la zona del negocio
+}
+
+// AfectadoCierre es un turno cancelado por un cierre, con el aviso que se le
+// dejó al cliente y los horarios que se le proponen.
+type AfectadoCierre struct {
+	Turno        *Turno
+	Notificacion *Notificacion // nil si no hay canal de notificaciones
+	Sugerencias  []Sugerencia
+}
+
+// InformeCierre resume lo que hizo un cierre de emergencia.
+type InformeCierre struct {
+	Cierre    Cierre
+	Bloqueos  []*Bloqueo
+	Afectados []AfectadoCierre
+}
+
+// MensajeCierre arma el aviso para el cliente de un turno cancelado por un cierre.
+func MensajeCierre(t *Turno, motivo string, sugerencias []Sugerencia) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hola %s, tu turno del %s a las %s se canceló: %s.",
+		t.Cliente.Nombre, t.Fecha.Format("02/01"), t.Hora, motivo)
+	if len(sugerencias) > 0 {
+		opciones := make([]string, 0, len(sugerencias))
+		for _, s := range sugerencias {
+			opciones = append(opciones, fmt.Sprintf("%s a las %s", s.Fecha.Format("02/01"), s.Hora))
+		}
+		fmt.Fprintf(&b, " Te podemos ofrecer: %s.", strings.Join(opciones, ", "))
+	}
+	return b.String()
+}
@@ -1,12 +1,27 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
+
+var ErrBusquedaCorta = errors.New("la búsqueda debe tener al menos 2 caracteres")
 
 type Cliente struct {
 	ID                 string
 	Nombre             string
 	Telefono           string
 	PreferenciaHoraria PreferenciaHoraria
+
+	// Ausencias y CancelacionesTardias son totales históricos, los calcula el repositorio.
+	Ausencias            int
+	CancelacionesTardias int
+	Restriccion          RestriccionCliente
+	// RestriccionLevantada es cuándo se levantó la última restricción; los
+	// incumplimientos anteriores ya no cuentan para volver a restringirlo.
+	RestriccionLevantada time.Time
+	// Anonimizado es cuándo se borraron sus datos personales; cero si no se borraron.
+	Anonimizado time.Time
 }
 
 func NewCliente(id, nombre, telefono string, preferenciahoraria PreferenciaHoraria) *Cliente {
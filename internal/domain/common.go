@@ -40,6 +40,25 @@ func IsValidPreferenciaHoraria(p PreferenciaHoraria) bool {
 	}
 }
 
+// Rango devuelve la franja del día asociada a la preferencia: la mañana va hasta
+// las 13:00, la tarde hasta las 19:00 y la noche hasta el final del día.
+func (s PreferenciaHoraria) Rango() (desde, hasta TimeOfDay) {
+	switch s {
+	case Mañana:
+		return TimeOfDay{Hour: 0, Minute: 0}, TimeOfDay{Hour: 13, Minute: 0}
+	case Tarde:
+		return TimeOfDay{Hour: 13, Minute: 0}, TimeOfDay{Hour: 19, Minute: 0}
+	default:
+		return TimeOfDay{Hour: 19, Minute: 0}, TimeOfDay{Hour: 23, Minute: 59}
+	}
+}
+
+// Incluye indica si un turno que empieza a la hora dada cae en la franja preferida.
+func (s PreferenciaHoraria) Incluye(hora TimeOfDay) bool {
+	desde, hasta := s.Rango()
+	return !hora.IsBefore(desde) && hora.IsBefore(hasta)
+}
+
 type TimeOfDay struct {
 	Hour   int
 	Minute int
@@ -97,3 +116,25 @@ func ParseTimeOfDay(input string) (TimeOfDay, error) {
 func (t TimeOfDay) Equals(other TimeOfDay) bool {
 	return t.Hour == other.Hour && t.Minute == other.Minute
 }
+
+func (t TimeOfDay) IsBefore(other TimeOfDay) bool {
+	return other.IsAfter(t)
+}
+
+// Minutes devuelve los minutos transcurridos desde las 00:00.
+func (t TimeOfDay) Minutes() int {
+	return t.Hour*60 + t.Minute
+}
+
+// FinDelDia es la medianoche con que termina el día, 24:00. Solo vale como
+// final de un rango (un bloqueo de día completo va de 00:00 a 24:00), nunca
+// como hora de un turno.
+var FinDelDia = TimeOfDay{Hour: 24, Minute: 0}
+
+// ParseFinDeRango es como ParseTimeOfDay, pero acepta además "24:00" como FinDelDia.
+func ParseFinDeRango(input string) (TimeOfDay, error) {
+	if input == FinDelDia.String() {
+		return FinDelDia, nil
+	}
+	return ParseTimeOfDay(input)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// IntervaloGrillaPorDefecto es la separación entre horarios ofrecidos cuando la
+// consulta no indica otra.
+const IntervaloGrillaPorDefecto = 15 * time.Minute
+
+// MaxDiasDisponibilidad limita el rango de una búsqueda de horarios libres.
+const MaxDiasDisponibilidad = 31
+
+// ConsultaDisponibilidad describe qué se busca: entre qué fechas (ambas
+// inclusive), para cuánto tiempo y, opcionalmente, en qué franja del día.
+type ConsultaDisponibilidad struct {
+	Desde       time.Time
+	Hasta       time.Time
+	Duracion    time.Duration
+	ServicioIDs []string
+	Intervalo   time.Duration
+	Preferencia *PreferenciaHoraria
+}
+
+func (c *ConsultaDisponibilidad) Validate() error {
+	if c.Desde.IsZero() || c.Hasta.IsZero() {
+		return errors.New("fechas requeridas")
+	}
+	if c.Hasta.Before(c.Desde) {
+		return errors.New("la fecha hasta es anterior a desde")
+	}
+	if c.Hasta.Sub(c.Desde) >= MaxDiasDisponibilidad*24*time.Hour {
+		return errors.New("el rango de búsqueda no puede superar 31 días")
+	}
+	if c.Duracion <= 0 {
+		return errors.New("duración inválida")
+	}
+	if c.Intervalo <= 0 || c.Intervalo%time.Minute != 0 {
+		return errors.New("intervalo inválido")
+	}
+	return nil
+}
+
+// Disponibilidad son los horarios de inicio libres de un día.
+type Disponibilidad struct {
+	Fecha    time.Time
+	Horarios []TimeOfDay
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type EstadoTurno int
+
+const (
+	Pendiente EstadoTurno = iota
+	Confirmado
+	Cancelado
+	Completado
+	Ausente
+)
+
+func (e EstadoTurno) String() string {
+	return [...]string{"pendiente", "confirmado", "cancelado", "completado", "ausente"}[e]
+}
+
+func ParseEstadoTurno(s string) (EstadoTurno, error) {
+	switch s {
+	case "pendiente":
+		return Pendiente, nil
+	case "confirmado":
+		return Confirmado, nil
+	case "cancelado":
+		return Cancelado, nil
+	case "completado":
+		return Completado, nil
+	case "ausente":
+		return Ausente, nil
+	default:
+		return -1, fmt.Errorf("estado de turno no valido: %s", s)
+	}
+}
+
+// EstadosQueOcupan son los estados de un turno que bloquean su horario en la
+// agenda. Un turno cancelado libera el lugar.
+var EstadosQueOcupan = []EstadoTurno{Pendiente, Confirmado, Completado, Ausente}
+
+// transiciones lista a qué estados se puede pasar desde cada uno. Cancelado,
+// completado y ausente son finales.
+var transiciones = map[EstadoTurno][]EstadoTurno{
+	Pendiente:  {Confirmado, Cancelado, Completado, Ausente},
+	Confirmado: {Cancelado, Completado, Ausente},
+}
+
+func (e EstadoTurno) PuedePasarA(nuevo EstadoTurno) bool {
+	for _, permitido := range transiciones[e] {
+		if permitido == nuevo {
+			return true
+		}
+	}
+	return false
+}
+
+// EsFinal indica que el turno ya no admite cambios.
+func (e EstadoTurno) EsFinal() bool {
+	return len(transiciones[e]) == 0
+}
+
+var ErrTransicionInvalida = errors.New("cambio de estado no permitido")
+
+// EventoTurno queda en el historial de un turno cada vez que cambia su estado.
+type EventoTurno struct {
+	TurnoID        string
+	EstadoAnterior EstadoTurno
+	Estado         EstadoTurno
+	Fecha          time.Time
+	Detalle        string
+}
+
+// CambiarEstado aplica la transición si está permitida y devuelve el evento
+// que la documenta.
+func (t *Turno) CambiarEstado(nuevo EstadoTurno, ahora time.Time, detalle string) (EventoTurno, error) {
+	if !t.Estado.PuedePasarA(nuevo) {
+		return EventoTurno{}, fmt.Errorf("%w: de %s a %s", ErrTransicionInvalida, t.Estado, nuevo)
+	}
+	evento := EventoTurno{
+		TurnoID:        t.ID,
+		EstadoAnterior: t.Estado,
+		Estado:         nuevo,
+		Fecha:          ahora,
+		Detalle:        detalle,
+	}
+	t.Estado = nuevo
+	return evento, nil
+}
+
+// Reprogramar mueve el turno a otra fecha y hora y devuelve el evento que deja
+// constancia del horario anterior. El estado no cambia.
+func (t *Turno) Reprogramar(fecha time.Time, hora TimeOfDay, ahora time.Time) (EventoTurno, error) {
+	if t.Estado.EsFinal() {
+		return EventoTurno{}, fmt.Errorf("%w: el turno está %s", ErrTransicionInvalida, t.Estado)
+	}
+	if fecha.IsZero() || !hora.IsValid() {
+		return EventoTurno{}, errors.New("fecha u hora inválida")
+	}
+	evento := EventoTurno{
+		TurnoID:        t.ID,
+		EstadoAnterior: t.Estado,
+		Estado:         t.Estado,
+		Fecha:          ahora,
+		Detalle:        fmt.Sprintf("reprogramado, antes era el %s a las %s", t.Fecha.Format("2006/01/02"), t.Hora),
+	}
+	t.Fecha = fecha
+	t.Hora = hora
+	return evento, nil
+}
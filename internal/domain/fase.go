@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Fase es un tramo de un servicio. En las fases libres (la pose de un color o
+// de una keratina) el cliente espera y la peluquera puede atender a otro.
+type Fase struct {
+	Duracion time.Duration
+	Libre    bool
+}
+
+// validarFases controla que las fases alternen trabajo y pose, empiecen y
+// terminen con trabajo y sumen la duración del servicio.
+func validarFases(fases []Fase, duracion time.Duration) error {
+	if len(fases) == 0 {
+		return nil
+	}
+	if fases[len(fases)-1].Libre {
+		return errors.New("las fases deben terminar con trabajo")
+	}
+	var total time.Duration
+	for i, f := range fases {
+		if f.Duracion <= 0 {
+			return errors.New("duración de fase inválida")
+		}
+		if f.Libre != (i%2 == 1) {
+			return errors.New("las fases deben alternar trabajo y pose, empezando con trabajo")
+		}
+		total += f.Duracion
+	}
+	if total != duracion {
+		return errors.New("las fases deben sumar la duración del servicio")
+	}
+	return nil
+}
+
+// Tramo es un rango de tiempo en que la peluquera está ocupada con un turno.
+type Tramo struct {
+	Desde time.Time
+	Hasta time.Time
+}
+
+func (a Tramo) seSolapaCon(b Tramo) bool {
+	return a.Desde.Before(b.Hasta) && b.Desde.Before(a.Hasta)
+}
+
+// Tramos devuelve los rangos en que t ocupa a la peluquera, en orden: las fases
+// de trabajo de sus servicios, con la preparación pegada al primero y la
+// limpieza al último. Las fases libres quedan como huecos entre tramos.
+func (t *Turno) Tramos(general Margen) []Tramo {
+	var tramos []Tramo
+	desde := t.Inicio()
+	for _, f := range t.fases() {
+		hasta := desde.Add(f.Duracion)
+		switch n := len(tramos); {
+		case f.Libre:
+		case n > 0 && tramos[n-1].Hasta.Equal(desde):
+			// dos servicios seguidos sin pose en el medio son un solo tramo
+			tramos[n-1].Hasta = hasta
+		default:
+			tramos = append(tramos, Tramo{Desde: desde, Hasta: hasta})
+		}
+		desde = hasta
+	}
+	m := t.Margen(general)
+	tramos[0].Desde = tramos[0].Desde.Add(-m.Antes)
+	tramos[len(tramos)-1].Hasta = tramos[len(tramos)-1].Hasta.Add(m.Despues)
+	return tramos
+}
+
+// fases encadena las fases de los servicios de t; un servicio sin fases es una
+// sola fase de trabajo. Si el turno no tiene servicios, o se reservó con otra
+// duración que la de ellos, se lo toma entero como trabajo.
+func (t *Turno) fases() []Fase {
+	if len(t.Servicios) == 0 || DuracionTotal(t.Servicios) != t.Duracion {
+		return []Fase{{Duracion: t.Duracion}}
+	}
+	var fases []Fase
+	for _, s := range t.Servicios {
+		if len(s.Fases) == 0 {
+			fases = append(fases, Fase{Duracion: s.Duracion})
+			continue
+		}
+		fases = append(fases, s.Fases...)
+	}
+	return fases
+}
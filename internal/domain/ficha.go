@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrTurnoDeOtroCliente = errors.New("el turno no es de este cliente")
+
+// OxidanteMaximo es el volumen más alto de oxidante que se usa.
+const OxidanteMaximo = 40
+
+// EntradaFicha es un registro de la ficha técnica de un cliente: lo que se le
+// hizo en una visita, para repetirlo o ajustarlo la próxima vez.
+type EntradaFicha struct {
+	ID          string
+	ClienteID   string
+	TurnoID     string // vacío si no se refiere a un turno
+	Fecha       time.Time
+	Marca       string // de la tintura
+	Formula     string
+	Oxidante    int // volúmenes; 0 si no se usó
+	TiempoPose  time.Duration
+	TipoCabello string
+	Alergias    string
+	Notas       string
+}
+
+func (e *EntradaFicha) Validate() error {
+	if e.ClienteID == "" || e.Fecha.IsZero() {
+		return errors.New("cliente y fecha requeridos")
+	}
+	if e.Oxidante < 0 || e.Oxidante > OxidanteMaximo {
+		return errors.New("volumen de oxidante inválido")
+	}
+	if e.TiempoPose < 0 {
+		return errors.New("tiempo de pose inválido")
+	}
+	if e.Marca == "" && e.Formula == "" && e.TipoCabello == "" && e.Alergias == "" && e.Notas == "" {
+		return errors.New("la entrada de la ficha está vacía")
+	}
+	return nil
+}
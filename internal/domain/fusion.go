@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrFusionMismoCliente = errors.New("no se puede fusionar un cliente consigo mismo")
+
+// NombresSimilares es un par de clientes cuyos nombres se parecen, con la
+// similitud entre 0 y 1.
+type NombresSimilares struct {
+	IDs       [2]string
+	Similitud float64
+}
+
+// PosibleDuplicado es un par de clientes que podrían ser la misma persona.
+type PosibleDuplicado struct {
+	Clientes      [2]*Cliente
+	MismoTelefono bool    // los teléfonos coinciden una vez normalizados
+	Similitud     float64 // de los nombres; 0 si no se parecen
+}
+
+// Fusion pasa todo lo del cliente Duplicado al Sobreviviente y borra el
+// duplicado. Movidos lo completa el repositorio con cuántas filas de cada
+// tabla cambiaron de cliente.
+type Fusion struct {
+	ID            string
+	Sobreviviente *Cliente
+	Duplicado     *Cliente // como estaba antes de borrarlo
+	Movidos       map[string]int64
+	Fecha         time.Time
+}
+
+// Acciones que quedan en la auditoría de clientes.
+const AccionFusion = "fusion"
+
+// EntradaAuditoria registra una operación sobre los datos de un cliente para
+// poder revisarla después.
+type EntradaAuditoria struct {
+	ID        string
+	Accion    string
+	ClienteID string
+	Detalle   map[string]any
+	Fecha     time.Time
+}
+
+// Auditoria arma la entrada que deja la fusión, con los datos del duplicado
+// que se borró.
+func (f *Fusion) Auditoria() *EntradaAuditoria {
+	return &EntradaAuditoria{
+		ID:        f.ID,
+		Accion:    AccionFusion,
+		ClienteID: f.Sobreviviente.ID,
+		Detalle: map[string]any{
+			"duplicado": map[string]any{
+				"id":                 f.Duplicado.ID,
+				"nombre":             f.Duplicado.Nombre,
+				"telefono":           f.Duplicado.Telefono,
+				"preferenciaHoraria": f.Duplicado.PreferenciaHoraria.String(),
+				"restriccion":        f.Duplicado.Restriccion.String(),
+			},
+			"movidos": f.Movidos,
+		},
+		Fecha: f.Fecha,
+	}
+}
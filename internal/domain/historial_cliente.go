@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// HistorialCliente reúne todos los turnos de un cliente, pasados y futuros, con
+// un resumen de sus visitas. Solo cuentan como visita los turnos completados.
+type HistorialCliente struct {
+	ClienteID        string
+	Turnos           []*Turno
+	PrimeraVisita    time.Time
+	UltimaVisita     time.Time
+	Visitas          int
+	DiasEntreVisitas float64 // promedio; cero con menos de dos visitas
+	TotalPagado      float64
+}
+
+// NewHistorialCliente arma el resumen a partir de los turnos del cliente
+// ordenados por fecha y hora.
+func NewHistorialCliente(clienteID string, turnos []*Turno) *HistorialCliente {
+	h := &HistorialCliente{ClienteID: clienteID, Turnos: turnos}
+	for _, t := range turnos {
+		h.TotalPagado += t.Pagado()
+		if t.Estado != Completado {
+			continue
+		}
+		if h.Visitas == 0 {
+			h.PrimeraVisita = t.Fecha
+		}
+		h.UltimaVisita = t.Fecha
+		h.Visitas++
+	}
+	if h.Visitas > 1 {
+		dias := h.UltimaVisita.Sub(h.PrimeraVisita).Hours() / 24
+		h.DiasEntreVisitas = dias / float64(h.Visitas-1)
+	}
+	return h
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrFueraDeHorario se devuelve cuando un turno cae fuera del horario de atención.
+var ErrFueraDeHorario = errors.New("el turno está fuera del horario de atención")
+
+// IntervaloHorario es una franja de atención continua, por ejemplo 09:00 a 13:00.
+type IntervaloHorario struct {
+	Desde TimeOfDay
+	Hasta TimeOfDay
+}
+
+func (i IntervaloHorario) Validate() error {
+	if !i.Desde.IsValid() || !i.Hasta.IsValid() {
+		return errors.New("hora inválida")
+	}
+	if !i.Hasta.IsAfter(i.Desde) {
+		return fmt.Errorf("el intervalo %s-%s termina antes de empezar", i.Desde, i.Hasta)
+	}
+	return nil
+}
+
+// HorarioLaboral agrupa las franjas de atención de un día de la semana. Un día
+// sin intervalos está cerrado.
+type HorarioLaboral struct {
+	Dia        time.Weekday
+	Intervalos []IntervaloHorario
+}
+
+func NewHorarioLaboral(dia time.Weekday, intervalos []IntervaloHorario) *HorarioLaboral {
+	return &HorarioLaboral{
+		Dia:        dia,
+		Intervalos: intervalos,
+	}
+}
+
+// Validate ordena los intervalos y verifica que no se pisen entre sí.
+func (h *HorarioLaboral) Validate() error {
+	if h.Dia < time.Sunday || h.Dia > time.Saturday {
+		return errors.New("día inválido")
+	}
+	for _, i := range h.Intervalos {
+		if err := i.Validate(); err != nil {
+			return err
+		}
+	}
+	sort.Slice(h.Intervalos, func(a, b int) bool {
+		return h.Intervalos[a].Desde.IsBefore(h.Intervalos[b].Desde)
+	})
+	for i := 1; i < len(h.Intervalos); i++ {
+		if h.Intervalos[i].Desde.IsBefore(h.Intervalos[i-1].Hasta) {
+			return fmt.Errorf("los intervalos %s-%s y %s-%s se superponen",
+				h.Intervalos[i-1].Desde, h.Intervalos[i-1].Hasta, h.Intervalos[i].Desde, h.Intervalos[i].Hasta)
+		}
+	}
+	return nil
+}
+
+// Cubre indica si un turno que empieza a la hora dada y dura duracion entra
+// completo en alguna de las franjas del día.
+func (h *HorarioLaboral) Cubre(hora TimeOfDay, duracion time.Duration) bool {
+	desde := hora.Minutes()
+	hasta := desde + int(duracion/time.Minute)
+	for _, i := range h.Intervalos {
+		if desde >= i.Desde.Minutes() && hasta <= i.Hasta.Minutes() {
+			return true
+		}
+	}
+	return false
+}
+
+var nombresDia = [...]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"}
+
+func NombreDia(d time.Weekday) string {
+	return nombresDia[d]
+}
+
+var sinTildes = strings.NewReplacer("á", "a", "é", "e")
+
+// ParseDiaSemana acepta el nombre del día en castellano, con o sin tilde, o su
+// número (0 = domingo).
+func ParseDiaSemana(s string) (time.Weekday, error) {
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 6 {
+		return time.Weekday(n), nil
+	}
+	buscado := sinTildes.Replace(strings.ToLower(s))
+	for i, nombre := range nombresDia {
+		if buscado == sinTildes.Replace(nombre) {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("día de la semana no válido: %s", s)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrLimiteReservas = errors.New("el cliente alcanzó el límite de turnos reservados")
+
+// EstadosActivos son los estados de un turno que todavía está por atenderse.
+var EstadosActivos = []EstadoTurno{Pendiente, Confirmado}
+
+// LimiteReservas acota cuántos turnos a futuro puede tener abiertos un mismo
+// cliente, en total y dentro de una semana (de lunes a domingo). Un valor en
+// cero no limita.
+type LimiteReservas struct {
+	Total     int
+	PorSemana int
+}
+
+func (l LimiteReservas) Activo() bool {
+	return l.Total > 0 || l.PorSemana > 0
+}
+
+func (l LimiteReservas) Validate() error {
+	if l.Total < 0 || l.PorSemana < 0 {
+		return errors.New("el límite de reservas no puede ser negativo")
+	}
+	return nil
+}
+
+// Permite indica si el cliente puede sumar nuevo a los turnos activos que ya
+// tiene. Si nuevo está entre ellos (porque se está moviendo) no se cuenta dos veces.
+func (l LimiteReservas) Permite(nuevo *Turno, activos []*Turno) error {
+	desde, hasta := SemanaDe(nuevo.Fecha)
+	total, enSemana := 0, 0
+	for _, t := range activos {
+		if t.ID == nuevo.ID {
+			continue
+		}
+		total++
+		if !t.Fecha.Before(desde) && !t.Fecha.After(hasta) {
+			enSemana++
+		}
+	}
+	if l.Total > 0 && total >= l.Total {
+		return fmt.Errorf("%w: ya tiene %d turnos por venir y el máximo es %d", ErrLimiteReservas, total, l.Total)
+	}
+	if l.PorSemana > 0 && enSemana >= l.PorSemana {
+		return fmt.Errorf("%w: ya tiene %d turnos la semana del %s y el máximo es %d",
+			ErrLimiteReservas, enSemana, desde.Format("02/01"), l.PorSemana)
+	}
+	return nil
+}
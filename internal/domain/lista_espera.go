@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EstadoEspera es la situación de un cliente anotado en la lista de espera.
+type EstadoEspera int
+
+const (
+	Esperando EstadoEspera = iota
+	ConOferta              // tiene una oferta sin responder
+	Atendida               // aceptó una oferta y ya tiene turno
+	Retirada               // se bajó de la lista
+)
+
+func (e EstadoEspera) String() string {
+	return [...]string{"esperando", "con oferta", "atendida", "retirada"}[e]
+}
+
+func ParseEstadoEspera(s string) (EstadoEspera, error) {
+	for e := Esperando; e <= Retirada; e++ {
+		if e.String() == s {
+			return e, nil
+		}
+	}
+	return -1, fmt.Errorf("estado de espera no válido: %s", s)
+}
+
+// EntradaEspera es un pedido de turno para cuando se libere un lugar entre
+// Desde y Hasta, opcionalmente en una franja del día.
+type EntradaEspera struct {
+	ID          string
+	Cliente     Cliente
+	Desde       time.Time
+	Hasta       time.Time
+	Preferencia *PreferenciaHoraria
+	Duracion    time.Duration
+	ServicioIDs []string
+	Estado      EstadoEspera
+	CreadaEn    time.Time
+	Oferta      *Oferta // la oferta pendiente, si hay una
+}
+
+func (e *EntradaEspera) Validate() error {
+	if e.Cliente.ID == "" {
+		return errors.New("cliente requerido")
+	}
+	if e.Desde.IsZero() || e.Hasta.IsZero() || e.Hasta.Before(e.Desde) {
+		return errors.New("rango de fechas inválido")
+	}
+	if e.Duracion <= 0 {
+		return errors.New("duración inválida")
+	}
+	if e.Preferencia != nil && !IsValidPreferenciaHoraria(*e.Preferencia) {
+		return errors.New("preferencia horaria no valida")
+	}
+	return nil
+}
+
+// Admite indica si el lugar que deja libre t le sirve a esta entrada: la fecha
+// cae en el rango, la hora en la franja preferida y el turno pedido entra en
+// el tiempo liberado.
+func (e *EntradaEspera) Admite(t *Turno) bool {
+	if e.Estado != Esperando {
+		return false
+	}
+	if t.Fecha.Before(e.Desde) || t.Fecha.After(e.Hasta) {
+		return false
+	}
+	if e.Preferencia != nil && !e.Preferencia.Incluye(t.Hora) {
+		return false
+	}
+	return e.Duracion <= t.Duracion
+}
+
+// EstadoOferta es cómo terminó (o si sigue abierta) una oferta de lugar.
+type EstadoOferta int
+
+const (
+	OfertaPendiente EstadoOferta = iota
+	OfertaAceptada
+	OfertaRechazada
+	OfertaVencida
+)
+
+func (e EstadoOferta) String() string {
+	return [...]string{"pendiente", "aceptada", "rechazada", "vencida"}[e]
+}
+
+func ParseEstadoOferta(s string) (EstadoOferta, error) {
+	for e := OfertaPendiente; e <= OfertaVencida; e++ {
+		if e.String() == s {
+			return e, nil
+		}
+	}
+	return -1, fmt.Errorf("estado de oferta no válido: %s", s)
+}
+
+var (
+	ErrOfertaVencida = errors.New("la oferta venció")
+	ErrOfertaCerrada = errors.New("la oferta ya fue respondida")
+	ErrLugarOfrecido = errors.New("el lugar está ofrecido a la lista de espera")
+)
+
+// Oferta es un lugar liberado que se le reserva a una entrada de la lista de
+// espera hasta VenceEn.
+type Oferta struct {
+	ID        string
+	EntradaID string
+	Fecha     time.Time
+	Hora      TimeOfDay
+	Duracion  time.Duration // la del lugar liberado, no la del turno pedido
+	VenceEn   time.Time
+	Estado    EstadoOferta
+	TurnoID   string // el turno creado al aceptarla
+	CreadaEn  time.Time
+}
+
+func (o *Oferta) Vencida(ahora time.Time) bool {
+	return !ahora.Before(o.VenceEn)
+}
+
+// Lugar devuelve el hueco ofrecido como un turno sin cliente, con el ID de la
+// oferta y la hora en zona, para comparar contra otros turnos o entradas.
+func (o *Oferta) Lugar(zona *time.Location) *Turno {
+	return &Turno{ID: o.ID, Fecha: o.Fecha, Hora: o.Hora, Duracion: o.Duracion, Zona: zona}
+}
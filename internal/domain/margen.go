@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// MargenMaximo acota el tiempo de preparación o limpieza que se puede configurar,
+// tanto en general como por servicio.
+const MargenMaximo = time.Hour
+
+// Margen es el tiempo que la estación queda ocupada antes (preparación) y después
+// (limpieza) de un turno. No forma parte del turno del cliente: solo se usa para
+// decidir si dos turnos chocan.
+type Margen struct {
+	Antes   time.Duration
+	Despues time.Duration
+}
+
+func (m Margen) Validate() error {
+	if !margenValido(m.Antes) || !margenValido(m.Despues) {
+		return errors.New("margen inválido")
+	}
+	return nil
+}
+
+func margenValido(d time.Duration) bool {
+	return d >= 0 && d <= MargenMaximo
+}
+
+// Margen devuelve el margen de t: de cada lado, el mayor entre los de sus
+// servicios. Los servicios que no lo definen, o un turno sin servicios, usan general.
+func (t *Turno) Margen(general Margen) Margen {
+	if len(t.Servicios) == 0 {
+		return general
+	}
+	var m Margen
+	for _, s := range t.Servicios {
+		antes, despues := general.Antes, general.Despues
+		if s.MargenAntes != nil {
+			antes = *s.MargenAntes
+		}
+		if s.MargenDespues != nil {
+			despues = *s.MargenDespues
+		}
+		m.Antes = max(m.Antes, antes)
+		m.Despues = max(m.Despues, despues)
+	}
+	return m
+}
+
+// Ocupa devuelve el rango en que t tiene tomada la estación, margen incluido.
+func (t *Turno) Ocupa(general Margen) (desde, hasta time.Time) {
+	m := t.Margen(general)
+	return t.Inicio().Add(-m.Antes), t.Fin().Add(m.Despues)
+}
+
+// ChocaCon indica si t y otro necesitan a la peluquera al mismo tiempo. A
+// diferencia de SeSolapaCon cuenta el margen de cada turno (entre la limpieza de
+// uno y la preparación del otro no puede haber superposición) y deja usar las
+// fases libres de uno para atender al otro.
+func (t *Turno) ChocaCon(otro *Turno, general Margen) bool {
+	otros := otro.Tramos(general)
+	for _, a := range t.Tramos(general) {
+		for _, b := range otros {
+			if a.seSolapaCon(b) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Notificacion es un mensaje para un cliente. Se guarda pendiente de envío
+// (EnviadaEn en cero) hasta que lo despache el canal que corresponda; por ahora
+// el único dato de contacto es el teléfono.
+type Notificacion struct {
+	ID        string
+	ClienteID string
+	TurnoID   string // vacío si no se refiere a un turno
+	Telefono  string
+	Mensaje   string
+	CreadaEn  time.Time
+	EnviadaEn time.Time
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrClienteAnonimizado = errors.New("los datos del cliente fueron anonimizados")
+	ErrClienteConTurnos   = errors.New("el cliente tiene turnos registrados; se puede anonimizar en lugar de borrar")
+	ErrTurnosPorVenir     = errors.New("el cliente tiene turnos por venir; hay que cancelarlos antes de anonimizarlo")
+)
+
+const (
+	AccionExportacion   = "exportacion"
+	AccionAnonimizacion = "anonimizacion"
+)
+
+// NombreAnonimizado reemplaza el nombre de un cliente anonimizado.
+const NombreAnonimizado = "Cliente anonimizado"
+
+// ExportacionCliente reúne todo lo que se guarda de un cliente (Ley 25.326),
+// tal como está en la base: su fila de cliente y, por tabla, las filas que le
+// pertenecen.
+type ExportacionCliente struct {
+	ClienteID string
+	Cliente   map[string]any
+	Tablas    map[string][]map[string]any
+	Fecha     time.Time
+}
+
+// Auditoria arma la entrada que deja la exportación. Solo registra cuántas
+// filas se entregaron de cada tabla, no los datos.
+func (e *ExportacionCliente) Auditoria(id string) *EntradaAuditoria {
+	filas := make(map[string]int, len(e.Tablas))
+	for tabla, f := range e.Tablas {
+		filas[tabla] = len(f)
+	}
+	return &EntradaAuditoria{
+		ID:        id,
+		Accion:    AccionExportacion,
+		ClienteID: e.ClienteID,
+		Detalle:   map[string]any{"filas": filas},
+		Fecha:     e.Fecha,
+	}
+}
+
+// Anonimizacion borra los datos personales de un cliente y conserva sus turnos
+// y pagos para las estadísticas. Borrados y Limpiados los completa el
+// repositorio con cuántas filas de cada tabla se borraron o se vaciaron.
+type Anonimizacion struct {
+	ID        string
+	ClienteID string
+	Borrados  map[string]int64
+	Limpiados map[string]int64
+	Fecha     time.Time
+}
+
+func (a *Anonimizacion) Auditoria() *EntradaAuditoria {
+	return &EntradaAuditoria{
+		ID:        a.ID,
+		Accion:    AccionAnonimizacion,
+		ClienteID: a.ClienteID,
+		Detalle:   map[string]any{"borrados": a.Borrados, "limpiados": a.Limpiados},
+		Fecha:     a.Fecha,
+	}
+}
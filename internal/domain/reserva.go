@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrTurnoPasado          = errors.New("el turno ya pasó")
+	ErrAnticipacionMinima   = errors.New("el turno no respeta la anticipación mínima para reservar")
+	ErrAnticipacionExcedida = errors.New("el turno supera la anticipación máxima para reservar")
+)
+
+// PoliticaReserva define con cuánta anticipación se puede sacar un turno: no
+// antes de AnticipacionMaxima (por ejemplo 60 días) ni después de que falte
+// menos de AnticipacionMinima (por ejemplo 2 horas). Un valor en cero no limita;
+// los turnos que ya empezaron se rechazan siempre.
+type PoliticaReserva struct {
+	AnticipacionMinima time.Duration
+	AnticipacionMaxima time.Duration
+}
+
+func (p PoliticaReserva) Validate() error {
+	if p.AnticipacionMinima < 0 || p.AnticipacionMaxima < 0 {
+		return errors.New("la anticipación no puede ser negativa")
+	}
+	if p.AnticipacionMaxima > 0 && p.AnticipacionMaxima < p.AnticipacionMinima {
+		return errors.New("la anticipación máxima debe ser mayor que la mínima")
+	}
+	return nil
+}
+
+// Permite indica si ahora se puede reservar un turno que empieza en inicio.
+func (p PoliticaReserva) Permite(inicio, ahora time.Time) error {
+	falta := inicio.Sub(ahora)
+	switch {
+	case falta <= 0:
+		return ErrTurnoPasado
+	case falta < p.AnticipacionMinima:
+		return fmt.Errorf("%w: se reserva con al menos %s", ErrAnticipacionMinima, p.AnticipacionMinima)
+	case p.AnticipacionMaxima > 0 && falta > p.AnticipacionMaxima:
+		return fmt.Errorf("%w: se reserva hasta %s antes", ErrAnticipacionExcedida, p.AnticipacionMaxima)
+	}
+	return nil
+}
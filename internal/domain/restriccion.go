@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RestriccionCliente es la medida que se le aplica a un cliente que falta seguido.
+type RestriccionCliente int
+
+const (
+	SinRestriccion RestriccionCliente = iota
+	RequiereSena
+	Bloqueado
+)
+
+func (r RestriccionCliente) String() string {
+	return [...]string{"ninguna", "seña", "bloqueado"}[r]
+}
+
+func ParseRestriccionCliente(s string) (RestriccionCliente, error) {
+	switch s {
+	case "ninguna", "":
+		return SinRestriccion, nil
+	case "seña", "sena":
+		return RequiereSena, nil
+	case "bloqueado", "bloqueo":
+		return Bloqueado, nil
+	default:
+		return -1, fmt.Errorf("restricción no válida: %s", s)
+	}
+}
+
+var (
+	ErrClienteBloqueado = errors.New("el cliente tiene las reservas bloqueadas")
+	ErrSenaRequerida    = errors.New("el cliente debe dejar una seña para reservar")
+)
+
+// ReglaAusencias define cuántos incumplimientos se toleran dentro de una ventana
+// de tiempo (por ejemplo 3 ausencias en 90 días) y qué restricción se aplica al
+// superarlos. Con Maximo en cero la regla queda desactivada.
+type ReglaAusencias struct {
+	Maximo        int
+	Ventana       time.Duration
+	ContarTardias bool // las cancelaciones tardías cuentan como ausencias
+	Accion        RestriccionCliente
+	SenaMinima    float64
+}
+
+func (r ReglaAusencias) Activa() bool {
+	return r.Maximo > 0 && r.Accion != SinRestriccion
+}
+
+func (r ReglaAusencias) Alcanzada(ausencias, tardias int) bool {
+	total := ausencias
+	if r.ContarTardias {
+		total += tardias
+	}
+	return r.Activa() && total >= r.Maximo
+}
+
+// PuedeReservar indica si el cliente puede sacar un turno dejando la seña indicada.
+func (c *Cliente) PuedeReservar(sena, senaMinima float64) error {
+	if !c.Anonimizado.IsZero() {
+		return ErrClienteAnonimizado
+	}
+	switch c.Restriccion {
+	case Bloqueado:
+		return ErrClienteBloqueado
+	case RequiereSena:
+		if sena <= 0 || sena < senaMinima {
+			return ErrSenaRequerida
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOcurrenciasSerie limita cuántos turnos puede generar una serie.
+const MaxOcurrenciasSerie = 52
+
+// ErrClienteDeSerie indica que se quiso pasar un turno de una serie a otro
+// cliente; la serie y todos sus turnos son de un mismo cliente.
+var ErrClienteDeSerie = errors.New("los turnos de una serie no cambian de cliente")
+
+// ReglaRecurrencia es el subconjunto de RRULE (RFC 5545) que se soporta:
+// FREQ=WEEKLY con INTERVAL y exactamente uno de COUNT o UNTIL.
+type ReglaRecurrencia struct {
+	Intervalo int       // cada cuántas semanas
+	Cantidad  int       // COUNT, cero si se usa Hasta
+	Hasta     time.Time // UNTIL, inclusive
+}
+
+// ParseRRule acepta reglas como "FREQ=WEEKLY;INTERVAL=3;COUNT=6", con o sin el
+// prefijo "RRULE:". UNTIL puede ser una fecha (20250901) o fecha y hora en UTC
+// (20250901T235959Z).
+func ParseRRule(s string) (ReglaRecurrencia, error) {
+	r := ReglaRecurrencia{Intervalo: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return r, errors.New("regla de recurrencia vacía")
+	}
+	var freq string
+	for _, parte := range strings.Split(s, ";") {
+		clave, valor, ok := strings.Cut(parte, "=")
+		if !ok {
+			return r, fmt.Errorf("regla de recurrencia mal formada: %s", parte)
+		}
+		var err error
+		switch strings.ToUpper(clave) {
+		case "FREQ":
+			freq = strings.ToUpper(valor)
+		case "INTERVAL":
+			if r.Intervalo, err = strconv.Atoi(valor); err != nil || r.Intervalo < 1 {
+				return r, fmt.Errorf("INTERVAL inválido: %s", valor)
+			}
+		case "COUNT":
+			if r.Cantidad, err = strconv.Atoi(valor); err != nil || r.Cantidad < 1 {
+				return r, fmt.Errorf("COUNT inválido: %s", valor)
+			}
+		case "UNTIL":
+			if r.Hasta, err = parseUntil(valor); err != nil {
+				return r, fmt.Errorf("UNTIL inválido: %s", valor)
+			}
+		default:
+			return r, fmt.Errorf("%s no está soportado en la regla de recurrencia", clave)
+		}
+	}
+	if freq != "WEEKLY" {
+		return r, errors.New("solo se admite FREQ=WEEKLY")
+	}
+	return r, r.Validate()
+}
+
+func parseUntil(v string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", v); err == nil {
+		return t, nil
+	}
+	return time.Parse("20060102", v)
+}
+
+func (r ReglaRecurrencia) Validate() error {
+	if r.Intervalo < 1 {
+		return errors.New("INTERVAL inválido")
+	}
+	if r.Cantidad > 0 && !r.Hasta.IsZero() {
+		return errors.New("COUNT y UNTIL no se pueden usar juntos")
+	}
+	if r.Cantidad == 0 && r.Hasta.IsZero() {
+		return errors.New("la regla de recurrencia necesita COUNT o UNTIL")
+	}
+	if r.Cantidad > MaxOcurrenciasSerie {
+		return fmt.Errorf("una serie puede tener hasta %d turnos", MaxOcurrenciasSerie)
+	}
+	return nil
+}
+
+// String devuelve la regla en formato RRULE, sin el prefijo.
+func (r ReglaRecurrencia) String() string {
+	s := fmt.Sprintf("FREQ=WEEKLY;INTERVAL=%d", r.Intervalo)
+	if r.Cantidad > 0 {
+		return s + fmt.Sprintf(";COUNT=%d", r.Cantidad)
+	}
+	return s + ";UNTIL=" + r.Hasta.UTC().Format("20060102T150405Z")
+}
+
+// Fechas devuelve las fechas de cada ocurrencia, empezando por inicio.
+func (r ReglaRecurrencia) Fechas(inicio time.Time) ([]time.Time, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	var fechas []time.Time
+	for i := 0; ; i++ {
+		f := inicio.AddDate(0, 0, 7*r.Intervalo*i)
+		if r.Cantidad > 0 && i >= r.Cantidad {
+			break
+		}
+		if !r.Hasta.IsZero() && f.After(r.Hasta) {
+			break
+		}
+		if len(fechas) == MaxOcurrenciasSerie {
+			return nil, fmt.Errorf("una serie puede tener hasta %d turnos", MaxOcurrenciasSerie)
+		}
+		fechas = append(fechas, f)
+	}
+	if len(fechas) == 0 {
+		return nil, errors.New("la regla de recurrencia no genera ningún turno")
+	}
+	return fechas, nil
+}
+
+// Serie agrupa los turnos generados a partir de una misma regla. Guarda la regla
+// y la fecha del primer turno tal como se crearon; los turnos pueden editarse
+// después sin que la serie cambie.
+type Serie struct {
+	ID        string
+	ClienteID string
+	Regla     ReglaRecurrencia
+	Inicio    time.Time
+	Turnos    []*Turno
+}
+
+// AlcanceSerie indica a qué turnos de una serie se aplica una edición o cancelación.
+type AlcanceSerie int
+
+const (
+	SoloEste AlcanceSerie = iota
+	EsteYSiguientes
+	TodaLaSerie
+)
+
+func (a AlcanceSerie) String() string {
+	return [...]string{"este", "siguientes", "todos"}[a]
+}
+
+func ParseAlcanceSerie(s string) (AlcanceSerie, error) {
+	switch s {
+	case "este", "":
+		return SoloEste, nil
+	case "siguientes":
+		return EsteYSiguientes, nil
+	case "todos":
+		return TodaLaSerie, nil
+	default:
+		return -1, fmt.Errorf("alcance no válido: %s", s)
+	}
+}
+
+// Incluye indica si o entra en el alcance cuando la acción parte de elegido.
+func (a AlcanceSerie) Incluye(elegido, o *Turno) bool {
+	switch a {
+	case TodaLaSerie:
+		return true
+	case EsteYSiguientes:
+		return !o.Inicio().Before(elegido.Inicio())
+	default:
+		return o.ID == elegido.ID
+	}
+}
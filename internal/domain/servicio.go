@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrServicioRepetido indica que se pidió el mismo servicio más de una vez en
+// un turno.
+var ErrServicioRepetido = errors.New("servicio repetido")
+
+type Servicio struct {
+	ID       string
+	Nombre   string
+	Duracion time.Duration
+	Precio   float64
+	Activo   bool
+	// MargenAntes y MargenDespues reemplazan al margen general para este
+	// servicio; nil usa el general.
+	MargenAntes   *time.Duration
+	MargenDespues *time.Duration
+	// Fases divide la duración en trabajo y pose; vacío es todo trabajo.
+	Fases []Fase
+}
+
+func NewServicio(id, nombre string, duracion time.Duration, precio float64, activo bool) *Servicio {
+	return &Servicio{
+		ID:       id,
+		Nombre:   nombre,
+		Duracion: duracion,
+		Precio:   precio,
+		Activo:   activo,
+	}
+}
+
+func (s *Servicio) Validate() error {
+	if s.Nombre == "" || s.Duracion <= 0 || s.Precio < 0 {
+		return errors.New("campos no válidos")
+	}
+	for _, m := range []*time.Duration{s.MargenAntes, s.MargenDespues} {
+		if m != nil && !margenValido(*m) {
+			return errors.New("margen inválido")
+		}
+	}
+	return validarFases(s.Fases, s.Duracion)
+}
+
+// DuracionTotal suma la duración de los servicios, que se realizan uno detrás del otro.
+func DuracionTotal(servicios []Servicio) time.Duration {
+	var total time.Duration
+	for _, s := range servicios {
+		total += s.Duracion
+	}
+	return total
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrTelefonoInvalido  = errors.New("teléfono inválido")
+	ErrTelefonoDuplicado = errors.New("el teléfono ya está registrado")
+)
+
+// TelefonoDuplicadoError indica qué cliente ya tiene el teléfono.
+type TelefonoDuplicadoError struct {
+	Cliente *Cliente
+}
+
+func (e *TelefonoDuplicadoError) Error() string {
+	return fmt.Sprintf("%s a nombre de %s (%s)", ErrTelefonoDuplicado, e.Cliente.Nombre, e.Cliente.ID)
+}
+
+func (e *TelefonoDuplicadoError) Unwrap() error {
+	return ErrTelefonoDuplicado
+}
+
+// NormalizarTelefono lleva tel a formato E.164. Los números argentinos se
+// toman como celulares, que es lo que dejan los clientes: se les agrega el 9
+// después del 54 y se les saca el 0 de larga distancia y el 15, así
+// "11 5555-1234", "011 15 5555-1234", "15 5555-1234" y "+54 9 11 5555-1234"
+// quedan todos como "+5491155551234". A los que se cargan sin código de área
+// se les agrega area. De otros países solo se controla el largo.
+func NormalizarTelefono(tel, area string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(tel) {
+		switch {
+		case r >= '0' && r <= '9', r == '+' && i == 0:
+			b.WriteRune(r)
+		case strings.ContainsRune(" -().", r):
+		default:
+			return "", fmt.Errorf("%w: %q", ErrTelefonoInvalido, tel)
+		}
+	}
+	s := b.String()
+	internacional := strings.HasPrefix(s, "+") || strings.HasPrefix(s, "00")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "+"), "00")
+	if internacional {
+		if !strings.HasPrefix(s, "54") {
+			if len(s) < 8 || len(s) > 15 {
+				return "", fmt.Errorf("%w: %q", ErrTelefonoInvalido, tel)
+			}
+			return "+" + s, nil
+		}
+		s = strings.TrimPrefix(s[2:], "9")
+	}
+	nacional, ok := numeroNacional(s, area)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrTelefonoInvalido, tel)
+	}
+	return "+549" + nacional, nil
+}
+
+// numeroNacional devuelve los 10 dígitos de área y número de un teléfono
+// argentino escrito como se marca dentro del país, con o sin 0 y 15.
+func numeroNacional(s, area string) (string, bool) {
+	s = strings.TrimPrefix(s, "0")
+	switch {
+	case len(s) == 12:
+		// área, 15 y número: el área es 11 o tiene 3 o 4 dígitos
+		for _, n := range []int{2, 3, 4} {
+			if s[n:n+2] == "15" && (n != 2 || s[:2] == "11") {
+				return s[:n] + s[n+2:], true
+			}
+		}
+		return "", false
+	case strings.HasPrefix(s, "15") && len(area)+len(s)-2 == 10:
+		// celular local sin código de área (ningún área empieza con 15)
+		return area + s[2:], true
+	case len(s) == 10:
+		return s, true
+	case len(area)+len(s) == 10:
+		return area + s, true
+	}
+	return "", false
+}
@@ -3,22 +3,37 @@ package domain
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
+// DuracionPorDefecto se usa cuando un turno se reserva sin indicar duración.
+const DuracionPorDefecto = 30 * time.Minute
+
 type Turno struct {
-	ID      string
-	Fecha   time.Time
-	Hora    TimeOfDay
-	Cliente Cliente
+	ID        string
+	Fecha     time.Time
+	Hora      TimeOfDay
+	Duracion  time.Duration
+	Cliente   Cliente
+	Servicios []Servicio
+	Estado    EstadoTurno
+	// Cancelacion solo está cargada en los turnos cancelados.
+	Cancelacion *Cancelacion
+	Sena        float64 // seña que dejó el cliente al reservar
+	SerieID     string  // vacío si el turno no es parte de una serie
+	// Zona es la zona horaria en la que se lee Hora; nil es UTC.
+	Zona *time.Location
 }
 
-func NewTurno(id string, fecha time.Time, hora TimeOfDay, cliente Cliente) *Turno {
+func NewTurno(id string, fecha time.Time, hora TimeOfDay, duracion time.Duration, cliente Cliente, servicios []Servicio) *Turno {
 	return &Turno{
-		ID:      id,
-		Fecha:   fecha,
-		Hora:    hora,
-		Cliente: cliente,
+		ID:        id,
+		Fecha:     fecha,
+		Hora:      hora,
+		Duracion:  duracion,
+		Cliente:   cliente,
+		Servicios: servicios,
 	}
 }
 
@@ -29,8 +44,104 @@ func (t *Turno) Validate() error {
 	if !t.Hora.IsValid() {
 		return errors.New("hora inválida")
 	}
+	if t.Duracion <= 0 {
+		return errors.New("duración inválida")
+	}
 	if err := t.Cliente.Validate(); err != nil {
 		return fmt.Errorf("cliente inválido: %w", err)
 	}
 	return nil
 }
+
+// Precio es la suma de los precios de los servicios reservados.
+func (t *Turno) Precio() float64 {
+	var total float64
+	for _, s := range t.Servicios {
+		total += s.Precio
+	}
+	return total
+}
+
+// Pagado es lo que dejó el cliente: la seña y, si el turno se completó, el
+// precio de lo que se hizo, que ya la incluye.
+func (t *Turno) Pagado() float64 {
+	if t.Estado == Completado {
+		return max(t.Precio(), t.Sena)
+	}
+	return t.Sena
+}
+
+// Inicio combina Fecha y Hora en un único instante, con la hora tomada en la
+// zona del turno.
+func (t *Turno) Inicio() time.Time {
+	zona := t.Zona
+	if zona == nil {
+		zona = time.UTC
+	}
+	return time.Date(t.Fecha.Year(), t.Fecha.Month(), t.Fecha.Day(), t.Hora.Hour, t.Hora.Minute, 0, 0, zona)
+}
+
+func (t *Turno) Fin() time.Time {
+	return t.Inicio().Add(t.Duracion)
+}
+
+// SeSolapaCon indica si los dos turnos comparten algún minuto. Que uno termine
+// justo cuando empieza el otro no cuenta como solapamiento.
+func (t *Turno) SeSolapaCon(otro *Turno) bool {
+	return t.Inicio().Before(otro.Fin()) && otro.Inicio().Before(t.Fin())
+}
+
+// Liberado devuelve lo que deja libre t al pasar a ocupar el horario de nuevo:
+// los pedazos de t que nuevo no cubre, como turnos sin ID ni cliente. Si no se
+// tocan, es t entero.
+func (t *Turno) Liberado(nuevo *Turno) []*Turno {
+	inicio, fin := t.Inicio(), t.Fin()
+	var pedazos []*Turno
+	agregar := func(desde, hasta time.Time) {
+		if !desde.Before(hasta) {
+			return
+		}
+		zona := t.Zona
+		if zona == nil {
+			zona = time.UTC
+		}
+		fecha, hora := Separar(desde, zona)
+		pedazos = append(pedazos, &Turno{Fecha: fecha, Hora: hora, Duracion: hasta.Sub(desde), Zona: t.Zona})
+	}
+	if !t.SeSolapaCon(nuevo) {
+		agregar(inicio, fin)
+		return pedazos
+	}
+	agregar(inicio, nuevo.Inicio())
+	agregar(nuevo.Fin(), fin)
+	return pedazos
+}
+
+// ConflictoTurnoError se devuelve cuando un turno se superpone con otros ya reservados.
+type ConflictoTurnoError struct {
+	IDs []string
+}
+
+func (e *ConflictoTurnoError) Error() string {
+	return fmt.Sprintf("el turno se superpone con: %s", strings.Join(e.IDs, ", "))
+}
+
+// TurnosEnRango lee los turnos no cancelados que ocupan al menos un minuto de
+// [desde, hasta). El repositorio la usa para pasarle a quien verifica
+// superposiciones los turnos que ve dentro de su transacción.
+type TurnosEnRango func(desde, hasta time.Time) ([]*Turno, error)
+
+// FiltroTurnos son los criterios para listar turnos. Los campos vacíos no filtran.
+type FiltroTurnos struct {
+	Desde     time.Time // inclusive
+	Hasta     time.Time // inclusive
+	ClienteID string
+	Estados   []EstadoTurno
+}
+
+func (f FiltroTurnos) Validate() error {
+	if !f.Desde.IsZero() && !f.Hasta.IsZero() && f.Hasta.Before(f.Desde) {
+		return errors.New("rango de fechas inválido")
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Las fechas del dominio son días de calendario (medianoche UTC, como las
+// devuelven time.Parse y las columnas DATE); las horas se interpretan en la
+// zona en la que atiende el negocio. Esa zona no es global: la reciben los
+// servicios y repositorios al construirse, y cada turno lleva la suya.
+
+// InicioDelDia es el instante en que empieza el día fecha en zona.
+func InicioDelDia(fecha time.Time, zona *time.Location) time.Time {
+	return time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, zona)
+}
+
+// Separar descompone un instante en el día de calendario y la hora que marca el
+// reloj del negocio en ese momento.
+func Separar(instante time.Time, zona *time.Location) (time.Time, TimeOfDay) {
+	local := instante.In(zona)
+	fecha := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	return fecha, TimeOfDay{Hour: local.Hour(), Minute: local.Minute()}
+}
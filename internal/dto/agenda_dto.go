@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type AgendaResponse struct {
+	Desde string               `json:"desde"`
+	Hasta string               `json:"hasta"`
+	Dias  []*DiaAgendaResponse `json:"dias"`
+}
+
+type DiaAgendaResponse struct {
+	Fecha             string                 `json:"fecha"`
+	MinutosReservados int                    `json:"minutosReservados"`
+	MinutosAbiertos   int                    `json:"minutosAbiertos"`
+	Ocupacion         float64                `json:"ocupacion"` // de 0 a 1
+	Turnos            []*TurnoAgendaResponse `json:"turnos"`
+}
+
+// TurnoAgendaResponse suma al turno los datos de contacto del cliente, para no
+// tener que pedirlos uno por uno, y en los turnos por venir la última fórmula
+// de su ficha.
+type TurnoAgendaResponse struct {
+	*TurnoResponse
+	ClienteNombre   string         `json:"clienteNombre"`
+	ClienteTelefono string         `json:"clienteTelefono"`
+	UltimaFormula   *FichaResponse `json:"ultimaFormula,omitempty"`
+}
+
+func AgendaFromDomain(a *domain.Agenda) *AgendaResponse {
+	dias := make([]*DiaAgendaResponse, 0, len(a.Dias))
+	for _, d := range a.Dias {
+		turnos := make([]*TurnoAgendaResponse, 0, len(d.Turnos))
+		for _, t := range d.Turnos {
+			res := &TurnoAgendaResponse{
+				TurnoResponse:   TurnoFromDomain(t),
+				ClienteNombre:   t.Cliente.Nombre,
+				ClienteTelefono: t.Cliente.Telefono,
+			}
+			if f := a.Formulas[t.ID]; f != nil {
+				res.UltimaFormula = FichaFromDomain(f)
+			}
+			turnos = append(turnos, res)
+		}
+		dias = append(dias, &DiaAgendaResponse{
+			Fecha:             d.Fecha.Format(time.DateOnly),
+			MinutosReservados: int(d.Reservado / time.Minute),
+			MinutosAbiertos:   int(d.Abierto / time.Minute),
+			Ocupacion:         d.Ocupacion(),
+			Turnos:            turnos,
+		})
+	}
+	return &AgendaResponse{
+		Desde: a.Desde.Format(time.DateOnly),
+		Hasta: a.Hasta.Format(time.DateOnly),
+		Dias:  dias,
+	}
+}
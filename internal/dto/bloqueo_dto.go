@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type BloqueoRequest struct {
+	ID            string `json:"id"`
+	Fecha         string `json:"fecha" validate:"required"` // YYYY-MM-DD
+	Desde         string `json:"desde" validate:"required"`
+	Hasta         string `json:"hasta" validate:"required"`
+	Motivo        string `json:"motivo" validate:"required"`
+	Semanal       bool   `json:"semanal"`
+	FinRepeticion string `json:"finRepeticion"` // YYYY-MM-DD, vacío: sin fin
+}
+
+func (r *BloqueoRequest) ToDomain() (*domain.Bloqueo, error) {
+	fecha, err := time.Parse(time.DateOnly, r.Fecha)
+	if err != nil {
+		return nil, err
+	}
+	desde, err := domain.ParseTimeOfDay(r.Desde)
+	if err != nil {
+		return nil, err
+	}
+	hasta, err := domain.ParseFinDeRango(r.Hasta)
+	if err != nil {
+		return nil, err
+	}
+	var fin time.Time
+	if r.FinRepeticion != "" {
+		if fin, err = time.Parse(time.DateOnly, r.FinRepeticion); err != nil {
+			return nil, err
+		}
+	}
+	return domain.NewBloqueo(r.ID, fecha, desde, hasta, r.Motivo, r.Semanal, fin), nil
+}
+
+type BloqueoResponse struct {
+	ID            string `json:"id"`
+	Fecha         string `json:"fecha"`
+	Desde         string `json:"desde"`
+	Hasta         string `json:"hasta"`
+	Motivo        string `json:"motivo"`
+	Semanal       bool   `json:"semanal"`
+	FinRepeticion string `json:"finRepeticion,omitempty"`
+}
+
+func BloqueoFromDomain(b *domain.Bloqueo) *BloqueoResponse {
+	res := &BloqueoResponse{
+		ID:      b.ID,
+		Fecha:   b.Fecha.Format(time.DateOnly),
+		Desde:   b.Desde.String(),
+		Hasta:   b.Hasta.String(),
+		Motivo:  b.Motivo,
+		Semanal: b.Semanal,
+	}
+	if !b.FinRepeticion.IsZero() {
+		res.FinRepeticion = b.FinRepeticion.Format(time.DateOnly)
+	}
+	return res
+}
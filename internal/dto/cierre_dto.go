@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type CierreRequest struct {
+	Desde  string `json:"desde"` // YYYY-MM-DD
+	Hasta  string `json:"hasta"` // si no se envía, se cierra solo desde
+	Motivo string `json:"motivo"`
+}
+
+func (r *CierreRequest) ToDomain() (domain.Cierre, error) {
+	desde, err := time.Parse(time.DateOnly, r.Desde)
+	if err != nil {
+		return domain.Cierre{}, errors.New("formato de fecha invalido, se esperaba YYYY-MM-DD")
+	}
+	hasta := desde
+	if r.Hasta != "" {
+		if hasta, err = time.Parse(time.DateOnly, r.Hasta); err != nil {
+			return domain.Cierre{}, errors.New("formato de fecha invalido, se esperaba YYYY-MM-DD")
+		}
+	}
+	return domain.Cierre{Desde: desde, Hasta: hasta, Motivo: r.Motivo}, nil
+}
+
+type CierreResponse struct {
+	Desde      string                    `json:"desde"`
+	Hasta      string                    `json:"hasta"`
+	Motivo     string                    `json:"motivo"`
+	BloqueoIDs []string                  `json:"bloqueoIDs"`
+	Afectados  []*AfectadoCierreResponse `json:"afectados"`
+}
+
+type AfectadoCierreResponse struct {
+	Turno        *TurnoAgendaResponse  `json:"turno"`
+	Notificacion string                `json:"notificacion,omitempty"` // el mensaje que se dejó para el cliente
+	Sugerencias  []*SugerenciaResponse `json:"sugerencias"`
+}
+
+type SugerenciaResponse struct {
+	Fecha  string `json:"fecha"` // YYYY/MM/DD, como en TurnoRequest
+	Hora   string `json:"hora"`
+	Inicio string `json:"inicio"`
+}
+
+func CierreFromDomain(i *domain.InformeCierre) *CierreResponse {
+	res := &CierreResponse{
+		Desde:      i.Cierre.Desde.Format(time.DateOnly),
+		Hasta:      i.Cierre.Hasta.Format(time.DateOnly),
+		Motivo:     i.Cierre.Motivo,
+		BloqueoIDs: make([]string, 0, len(i.Bloqueos)),
+		Afectados:  make([]*AfectadoCierreResponse, 0, len(i.Afectados)),
+	}
+	for _, b := range i.Bloqueos {
+		res.BloqueoIDs = append(res.BloqueoIDs, b.ID)
+	}
+	for _, a := range i.Afectados {
+		afectado := &AfectadoCierreResponse{
+			Turno: &TurnoAgendaResponse{
+				TurnoResponse:   TurnoFromDomain(a.Turno),
+				ClienteNombre:   a.Turno.Cliente.Nombre,
+				ClienteTelefono: a.Turno.Cliente.Telefono,
+			},
+			Sugerencias: make([]*SugerenciaResponse, 0, len(a.Sugerencias)),
+		}
+		if a.Notificacion != nil {
+			afectado.Notificacion = a.Notificacion.Mensaje
+		}
+		for _, s := range a.Sugerencias {
+			afectado.Sugerencias = append(afectado.Sugerencias, &SugerenciaResponse{
+				Fecha:  s.Fecha.Format("2006/01/02"),
+				Hora:   s.Hora.String(),
+				Inicio: instante(s.Inicio),
+			})
+		}
+		res.Afectados = append(res.Afectados, afectado)
+	}
+	return res
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
 )
 
@@ -25,17 +27,72 @@ func (r *ClienteRequest) ToDomain() (*domain.Cliente, error) {
 }
 
 type ClienteResponse struct {
-	ID                 string `json:"id"`
-	Nombre             string `json:"nombre"`
-	Telefono           string `json:"telefono"`
-	PreferenciaHoraria string `json:"preferenciaHoraria"`
+	ID                   string `json:"id"`
+	Nombre               string `json:"nombre"`
+	Telefono             string `json:"telefono"`
+	PreferenciaHoraria   string `json:"preferenciaHoraria"`
+	Ausencias            int    `json:"ausencias"`
+	CancelacionesTardias int    `json:"cancelacionesTardias"`
+	Restriccion          string `json:"restriccion"`
+	Anonimizado          string `json:"anonimizado,omitempty"` // cuándo se borraron sus datos personales
 }
 
 func ClienteFromDomain(c *domain.Cliente) *ClienteResponse {
-	return &ClienteResponse{
-		ID:                 c.ID,
-		Nombre:             c.Nombre,
-		Telefono:           c.Telefono,
-		PreferenciaHoraria: c.PreferenciaHoraria.String(),
+	res := &ClienteResponse{
+		ID:                   c.ID,
+		Nombre:               c.Nombre,
+		Telefono:             c.Telefono,
+		PreferenciaHoraria:   c.PreferenciaHoraria.String(),
+		Ausencias:            c.Ausencias,
+		CancelacionesTardias: c.CancelacionesTardias,
+		Restriccion:          c.Restriccion.String(),
+	}
+	if !c.Anonimizado.IsZero() {
+		res.Anonimizado = c.Anonimizado.Format(time.RFC3339)
+	}
+	return res
+}
+
+type HistorialClienteResponse struct {
+	ClienteID        string                    `json:"clienteID"`
+	PrimeraVisita    string                    `json:"primeraVisita,omitempty"`
+	UltimaVisita     string                    `json:"ultimaVisita,omitempty"`
+	Visitas          int                       `json:"visitas"`
+	DiasEntreVisitas float64                   `json:"diasEntreVisitas"`
+	TotalPagado      float64                   `json:"totalPagado"`
+	Turnos           []*TurnoHistorialResponse `json:"turnos"`
+}
+
+// TurnoHistorialResponse suma al turno los nombres de sus servicios y lo pagado.
+type TurnoHistorialResponse struct {
+	*TurnoResponse
+	Servicios []string `json:"servicios"`
+	Pagado    float64  `json:"pagado"`
+}
+
+func HistorialClienteFromDomain(h *domain.HistorialCliente) *HistorialClienteResponse {
+	turnos := make([]*TurnoHistorialResponse, 0, len(h.Turnos))
+	for _, t := range h.Turnos {
+		servicios := make([]string, 0, len(t.Servicios))
+		for _, s := range t.Servicios {
+			servicios = append(servicios, s.Nombre)
+		}
+		turnos = append(turnos, &TurnoHistorialResponse{
+			TurnoResponse: TurnoFromDomain(t),
+			Servicios:     servicios,
+			Pagado:        t.Pagado(),
+		})
+	}
+	res := &HistorialClienteResponse{
+		ClienteID:        h.ClienteID,
+		Visitas:          h.Visitas,
+		DiasEntreVisitas: h.DiasEntreVisitas,
+		TotalPagado:      h.TotalPagado,
+		Turnos:           turnos,
+	}
+	if h.Visitas > 0 {
+		res.PrimeraVisita = h.PrimeraVisita.Format(time.DateOnly)
+		res.UltimaVisita = h.UltimaVisita.Format(time.DateOnly)
 	}
+	return res
 }
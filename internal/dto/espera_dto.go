@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type EsperaRequest struct {
+	ClienteID   string   `json:"clienteID" validate:"required"`
+	Desde       string   `json:"desde" validate:"required"` // YYYY-MM-DD
+	Hasta       string   `json:"hasta" validate:"required"`
+	Preferencia string   `json:"preferencia"` // vacío: cualquier horario
+	Duracion    int      `json:"duracion"`    // en minutos, solo se usa si no hay servicios
+	ServicioIDs []string `json:"servicioIDs"`
+}
+
+func (r *EsperaRequest) ToDomain() (*domain.EntradaEspera, error) {
+	desde, err := time.Parse(time.DateOnly, r.Desde)
+	if err != nil {
+		return nil, err
+	}
+	hasta, err := time.Parse(time.DateOnly, r.Hasta)
+	if err != nil {
+		return nil, err
+	}
+	e := &domain.EntradaEspera{
+		Cliente:     domain.Cliente{ID: r.ClienteID},
+		Desde:       desde,
+		Hasta:       hasta,
+		Duracion:    time.Duration(r.Duracion) * time.Minute,
+		ServicioIDs: r.ServicioIDs,
+	}
+	if r.Preferencia != "" {
+		p, err := domain.ParsePreferenciaHoraria(r.Preferencia)
+		if err != nil {
+			return nil, err
+		}
+		e.Preferencia = &p
+	}
+	return e, nil
+}
+
+type EsperaResponse struct {
+	ID          string          `json:"id"`
+	ClienteID   string          `json:"clienteID"`
+	Desde       string          `json:"desde"`
+	Hasta       string          `json:"hasta"`
+	Preferencia string          `json:"preferencia,omitempty"`
+	Duracion    int             `json:"duracion"`
+	ServicioIDs []string        `json:"servicioIDs"`
+	Estado      string          `json:"estado"`
+	CreadaEn    string          `json:"creadaEn"`
+	Oferta      *OfertaResponse `json:"oferta,omitempty"`
+}
+
+func EsperaFromDomain(e *domain.EntradaEspera) *EsperaResponse {
+	res := &EsperaResponse{
+		ID:          e.ID,
+		ClienteID:   e.Cliente.ID,
+		Desde:       e.Desde.Format(time.DateOnly),
+		Hasta:       e.Hasta.Format(time.DateOnly),
+		Duracion:    int(e.Duracion / time.Minute),
+		ServicioIDs: e.ServicioIDs,
+		Estado:      e.Estado.String(),
+		CreadaEn:    instante(e.CreadaEn),
+	}
+	if e.Preferencia != nil {
+		res.Preferencia = e.Preferencia.String()
+	}
+	if e.Oferta != nil {
+		res.Oferta = OfertaFromDomain(e.Oferta)
+	}
+	return res
+}
+
+type OfertaResponse struct {
+	ID      string `json:"id"`
+	Fecha   string `json:"fecha"`
+	Hora    string `json:"hora"`
+	VenceEn string `json:"venceEn"`
+	Estado  string `json:"estado"`
+}
+
+func OfertaFromDomain(o *domain.Oferta) *OfertaResponse {
+	return &OfertaResponse{
+		ID:      o.ID,
+		Fecha:   o.Fecha.Format(time.DateOnly),
+		Hora:    o.Hora.String(),
+		VenceEn: instante(o.VenceEn),
+		Estado:  o.Estado.String(),
+	}
+}
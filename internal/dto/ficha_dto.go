@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type FichaRequest struct {
+	ID          string `json:"id"`
+	TurnoID     string `json:"turnoID"`
+	Fecha       string `json:"fecha"` // YYYY-MM-DD, hoy si no se envía
+	Marca       string `json:"marca"`
+	Formula     string `json:"formula"`
+	Oxidante    int    `json:"oxidante"`   // volúmenes
+	TiempoPose  int    `json:"tiempoPose"` // en minutos
+	TipoCabello string `json:"tipoCabello"`
+	Alergias    string `json:"alergias"`
+	Notas       string `json:"notas"`
+}
+
+// ToDomain arma la entrada para el cliente de la URL.
+func (r *FichaRequest) ToDomain(clienteID string) (*domain.EntradaFicha, error) {
+	var fecha time.Time
+	if r.Fecha != "" {
+		var err error
+		if fecha, err = time.Parse(time.DateOnly, r.Fecha); err != nil {
+			return nil, errors.New("formato de fecha invalido, se esperaba YYYY-MM-DD")
+		}
+	}
+	return &domain.EntradaFicha{
+		ID:          r.ID,
+		ClienteID:   clienteID,
+		TurnoID:     r.TurnoID,
+		Fecha:       fecha,
+		Marca:       r.Marca,
+		Formula:     r.Formula,
+		Oxidante:    r.Oxidante,
+		TiempoPose:  time.Duration(r.TiempoPose) * time.Minute,
+		TipoCabello: r.TipoCabello,
+		Alergias:    r.Alergias,
+		Notas:       r.Notas,
+	}, nil
+}
+
+type FichaResponse struct {
+	ID          string `json:"id"`
+	ClienteID   string `json:"clienteID"`
+	TurnoID     string `json:"turnoID,omitempty"`
+	Fecha       string `json:"fecha"`
+	Marca       string `json:"marca,omitempty"`
+	Formula     string `json:"formula,omitempty"`
+	Oxidante    int    `json:"oxidante,omitempty"`
+	TiempoPose  int    `json:"tiempoPose,omitempty"`
+	TipoCabello string `json:"tipoCabello,omitempty"`
+	Alergias    string `json:"alergias,omitempty"`
+	Notas       string `json:"notas,omitempty"`
+}
+
+func FichaFromDomain(e *domain.EntradaFicha) *FichaResponse {
+	return &FichaResponse{
+		ID:          e.ID,
+		ClienteID:   e.ClienteID,
+		TurnoID:     e.TurnoID,
+		Fecha:       e.Fecha.Format(time.DateOnly),
+		Marca:       e.Marca,
+		Formula:     e.Formula,
+		Oxidante:    e.Oxidante,
+		TiempoPose:  int(e.TiempoPose / time.Minute),
+		TipoCabello: e.TipoCabello,
+		Alergias:    e.Alergias,
+		Notas:       e.Notas,
+	}
+}
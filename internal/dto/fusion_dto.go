@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type PosibleDuplicadoResponse struct {
+	Clientes      []*ClienteResponse `json:"clientes"`
+	MismoTelefono bool               `json:"mismoTelefono"`
+	Similitud     float64            `json:"similitud"`
+}
+
+func PosibleDuplicadoFromDomain(p *domain.PosibleDuplicado) *PosibleDuplicadoResponse {
+	return &PosibleDuplicadoResponse{
+		Clientes:      []*ClienteResponse{ClienteFromDomain(p.Clientes[0]), ClienteFromDomain(p.Clientes[1])},
+		MismoTelefono: p.MismoTelefono,
+		Similitud:     p.Similitud,
+	}
+}
+
+type FusionRequest struct {
+	DuplicadoID string `json:"duplicadoID"`
+}
+
+type FusionResponse struct {
+	ID        string           `json:"id"`
+	Cliente   *ClienteResponse `json:"cliente"`   // el sobreviviente, ya con todo lo del duplicado
+	Duplicado *ClienteResponse `json:"duplicado"` // como estaba antes de borrarlo
+	Movidos   map[string]int64 `json:"movidos"`
+	Fecha     string           `json:"fecha"`
+}
+
+func FusionFromDomain(f *domain.Fusion) *FusionResponse {
+	return &FusionResponse{
+		ID:        f.ID,
+		Cliente:   ClienteFromDomain(f.Sobreviviente),
+		Duplicado: ClienteFromDomain(f.Duplicado),
+		Movidos:   f.Movidos,
+		Fecha:     f.Fecha.Format(time.RFC3339),
+	}
+}
+
+type EntradaAuditoriaResponse struct {
+	ID        string         `json:"id"`
+	Accion    string         `json:"accion"`
+	ClienteID string         `json:"clienteID"`
+	Detalle   map[string]any `json:"detalle"`
+	Fecha     string         `json:"fecha"`
+}
+
+func EntradaAuditoriaFromDomain(e *domain.EntradaAuditoria) *EntradaAuditoriaResponse {
+	return &EntradaAuditoriaResponse{
+		ID:        e.ID,
+		Accion:    e.Accion,
+		ClienteID: e.ClienteID,
+		Detalle:   e.Detalle,
+		Fecha:     e.Fecha.Format(time.RFC3339),
+	}
+}
+
+// ExportacionResponse entrega las filas tal como están guardadas, con los
+// nombres de columna de la base.
+type ExportacionResponse struct {
+	ClienteID string                      `json:"clienteID"`
+	Cliente   map[string]any              `json:"cliente"`
+	Tablas    map[string][]map[string]any `json:"tablas"`
+	Fecha     string                      `json:"fecha"`
+}
+
+func ExportacionFromDomain(e *domain.ExportacionCliente) *ExportacionResponse {
+	return &ExportacionResponse{
+		ClienteID: e.ClienteID,
+		Cliente:   e.Cliente,
+		Tablas:    e.Tablas,
+		Fecha:     e.Fecha.Format(time.RFC3339),
+	}
+}
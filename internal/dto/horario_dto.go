@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type IntervaloHorarioDTO struct {
+	Desde string `json:"desde"`
+	Hasta string `json:"hasta"`
+}
+
+type HorarioRequest struct {
+	Intervalos []IntervaloHorarioDTO `json:"intervalos"`
+}
+
+func (r *HorarioRequest) ToDomain(dia time.Weekday) (*domain.HorarioLaboral, error) {
+	intervalos := make([]domain.IntervaloHorario, 0, len(r.Intervalos))
+	for _, i := range r.Intervalos {
+		desde, err := domain.ParseTimeOfDay(i.Desde)
+		if err != nil {
+			return nil, err
+		}
+		hasta, err := domain.ParseTimeOfDay(i.Hasta)
+		if err != nil {
+			return nil, err
+		}
+		intervalos = append(intervalos, domain.IntervaloHorario{Desde: desde, Hasta: hasta})
+	}
+	return domain.NewHorarioLaboral(dia, intervalos), nil
+}
+
+type HorarioResponse struct {
+	Dia        string                `json:"dia"`
+	Intervalos []IntervaloHorarioDTO `json:"intervalos"`
+}
+
+func HorarioFromDomain(h *domain.HorarioLaboral) *HorarioResponse {
+	intervalos := make([]IntervaloHorarioDTO, 0, len(h.Intervalos))
+	for _, i := range h.Intervalos {
+		intervalos = append(intervalos, IntervaloHorarioDTO{Desde: i.Desde.String(), Hasta: i.Hasta.String()})
+	}
+	return &HorarioResponse{
+		Dia:        domain.NombreDia(h.Dia),
+		Intervalos: intervalos,
+	}
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type ServicioRequest struct {
+	ID       string  `json:"id"`
+	Nombre   string  `json:"nombre" validate:"required"`
+	Duracion int     `json:"duracion" validate:"required"` // en minutos
+	Precio   float64 `json:"precio"`
+	Activo   *bool   `json:"activo"` // si no se envía, el servicio queda activo
+	// en minutos; si no se envían se usa el margen general
+	MargenAntes   *int `json:"margenAntes"`
+	MargenDespues *int `json:"margenDespues"`
+	// si se envían, tienen que alternar trabajo y pose y sumar la duración
+	Fases []FaseServicio `json:"fases"`
+}
+
+// FaseServicio es un tramo de trabajo o de pose (libre) de un servicio.
+type FaseServicio struct {
+	Duracion int  `json:"duracion"` // en minutos
+	Libre    bool `json:"libre"`
+}
+
+func (r *ServicioRequest) ToDomain() *domain.Servicio {
+	activo := true
+	if r.Activo != nil {
+		activo = *r.Activo
+	}
+	s := domain.NewServicio(
+		r.ID,
+		r.Nombre,
+		time.Duration(r.Duracion)*time.Minute,
+		r.Precio,
+		activo,
+	)
+	s.MargenAntes = duracionEnMinutos(r.MargenAntes)
+	s.MargenDespues = duracionEnMinutos(r.MargenDespues)
+	for _, f := range r.Fases {
+		s.Fases = append(s.Fases, domain.Fase{Duracion: time.Duration(f.Duracion) * time.Minute, Libre: f.Libre})
+	}
+	return s
+}
+
+type ServicioResponse struct {
+	ID       string  `json:"id"`
+	Nombre   string  `json:"nombre"`
+	Duracion int     `json:"duracion"`
+	Precio   float64 `json:"precio"`
+	Activo   bool    `json:"activo"`
+	// solo aparecen si el servicio reemplaza el margen general
+	MargenAntes   *int           `json:"margenAntes,omitempty"`
+	MargenDespues *int           `json:"margenDespues,omitempty"`
+	Fases         []FaseServicio `json:"fases,omitempty"`
+}
+
+func ServicioFromDomain(s *domain.Servicio) *ServicioResponse {
+	var fases []FaseServicio
+	for _, f := range s.Fases {
+		fases = append(fases, FaseServicio{Duracion: int(f.Duracion / time.Minute), Libre: f.Libre})
+	}
+	return &ServicioResponse{
+		ID:            s.ID,
+		Nombre:        s.Nombre,
+		Duracion:      int(s.Duracion / time.Minute),
+		Precio:        s.Precio,
+		Activo:        s.Activo,
+		MargenAntes:   enMinutos(s.MargenAntes),
+		MargenDespues: enMinutos(s.MargenDespues),
+		Fases:         fases,
+	}
+}
+
+func duracionEnMinutos(m *int) *time.Duration {
+	if m == nil {
+		return nil
+	}
+	d := time.Duration(*m) * time.Minute
+	return &d
+}
+
+func enMinutos(d *time.Duration) *int {
+	if d == nil {
+		return nil
+	}
+	m := int(*d / time.Minute)
+	return &m
+}
@@ -1,28 +1,168 @@
 package dto
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
 )
 
 type TurnoRequest struct {
-	ID        string `json:"id" validate:"required"`
-	Fecha     string `json:"fecha" validate:"required"`
-	Hora      string `json:"hora" validate:"required"`
-	ClienteID string `json:"clienteID" validate:"required"`
+	ID          string   `json:"id" validate:"required"`
+	Fecha       string   `json:"fecha" validate:"required"`
+	Hora        string   `json:"hora" validate:"required"`
+	Duracion    int      `json:"duracion"` // en minutos, solo se usa si no hay servicios
+	ClienteID   string   `json:"clienteID" validate:"required"`
+	ServicioIDs []string `json:"servicioIDs"`
+	Sena        float64  `json:"sena"`
 }
 
 type TurnoResponse struct {
-	ID        string `json:"id"`
-	Fecha     string `json:"fecha"`
-	Hora      string `json:"hora"`
-	ClienteID string `json:"clienteID"`
+	ID          string               `json:"id"`
+	Fecha       string               `json:"fecha"` // YYYY/MM/DD, como en TurnoRequest
+	Hora        string               `json:"hora"`
+	Inicio      string               `json:"inicio"` // RFC 3339 en la zona del negocio
+	Fin         string               `json:"fin"`
+	Duracion    int                  `json:"duracion"`
+	ClienteID   string               `json:"clienteID"`
+	ServicioIDs []string             `json:"servicioIDs"`
+	Precio      float64              `json:"precio"`
+	Estado      string               `json:"estado"`
+	Sena        float64              `json:"sena"`
+	SerieID     string               `json:"serieID,omitempty"`
+	Cancelacion *CancelacionResponse `json:"cancelacion,omitempty"`
 }
 
 func TurnoFromDomain(t *domain.Turno) *TurnoResponse {
+	servicioIDs := make([]string, 0, len(t.Servicios))
+	for _, s := range t.Servicios {
+		servicioIDs = append(servicioIDs, s.ID)
+	}
 	return &TurnoResponse{
-		ID:        t.ID,
-		Fecha:     t.Fecha.String(),
-		Hora:      t.Hora.String(),
-		ClienteID: t.Cliente.ID,
+		ID:          t.ID,
+		Fecha:       t.Fecha.Format("2006/01/02"),
+		Hora:        t.Hora.String(),
+		Inicio:      instante(t.Inicio()),
+		Fin:         instante(t.Fin()),
+		Duracion:    int(t.Duracion / time.Minute),
+		ClienteID:   t.Cliente.ID,
+		ServicioIDs: servicioIDs,
+		Precio:      t.Precio(),
+		Estado:      t.Estado.String(),
+		Sena:        t.Sena,
+		SerieID:     t.SerieID,
+		Cancelacion: CancelacionFromDomain(t.Cancelacion),
+	}
+}
+
+// instante da formato RFC 3339 a t. Los repositorios devuelven todas las fechas
+// en la zona horaria del negocio, así que el desfase es el de la zona.
+func instante(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+type SerieRequest struct {
+	TurnoRequest
+	RRule string `json:"rrule" validate:"required"`
+}
+
+type SerieResponse struct {
+	ID     string           `json:"id"`
+	RRule  string           `json:"rrule"`
+	Inicio string           `json:"inicio"`
+	Turnos []*TurnoResponse `json:"turnos"`
+}
+
+func SerieFromDomain(s *domain.Serie) *SerieResponse {
+	turnos := make([]*TurnoResponse, 0, len(s.Turnos))
+	for _, t := range s.Turnos {
+		turnos = append(turnos, TurnoFromDomain(t))
+	}
+	return &SerieResponse{
+		ID:     s.ID,
+		RRule:  s.Regla.String(),
+		Inicio: s.Inicio.Format(time.DateOnly),
+		Turnos: turnos,
+	}
+}
+
+type ReprogramacionRequest struct {
+	Fecha string `json:"fecha" validate:"required"` // YYYY/MM/DD, como en TurnoRequest
+	Hora  string `json:"hora" validate:"required"`
+}
+
+func (r *ReprogramacionRequest) ToDomain() (time.Time, domain.TimeOfDay, error) {
+	fecha, err := time.Parse("2006/01/02", r.Fecha)
+	if err != nil {
+		return time.Time{}, domain.TimeOfDay{}, fmt.Errorf("fecha inválida: %w", err)
+	}
+	hora, err := domain.ParseTimeOfDay(r.Hora)
+	if err != nil {
+		return time.Time{}, domain.TimeOfDay{}, fmt.Errorf("hora inválida: %w", err)
+	}
+	return fecha, hora, nil
+}
+
+type CancelacionRequest struct {
+	Origen string `json:"origen" validate:"required"` // cliente o negocio
+	Motivo string `json:"motivo" validate:"required"`
+}
+
+func (r *CancelacionRequest) ToDomain() (domain.Cancelacion, error) {
+	origen, err := domain.ParseOrigenCancelacion(r.Origen)
+	if err != nil {
+		return domain.Cancelacion{}, err
+	}
+	return domain.Cancelacion{Origen: origen, Motivo: r.Motivo}, nil
+}
+
+type CancelacionResponse struct {
+	Origen string `json:"origen"`
+	Motivo string `json:"motivo"`
+	Tardia bool   `json:"tardia"`
+	Fecha  string `json:"fecha"`
+}
+
+func CancelacionFromDomain(c *domain.Cancelacion) *CancelacionResponse {
+	if c == nil {
+		return nil
+	}
+	return &CancelacionResponse{
+		Origen: c.Origen.String(),
+		Motivo: c.Motivo,
+		Tardia: c.Tardia,
+		Fecha:  instante(c.Fecha),
+	}
+}
+
+type EventoTurnoResponse struct {
+	EstadoAnterior string `json:"estadoAnterior"`
+	Estado         string `json:"estado"`
+	Fecha          string `json:"fecha"`
+	Detalle        string `json:"detalle,omitempty"`
+}
+
+func EventoTurnoFromDomain(e domain.EventoTurno) *EventoTurnoResponse {
+	return &EventoTurnoResponse{
+		EstadoAnterior: e.EstadoAnterior.String(),
+		Estado:         e.Estado.String(),
+		Fecha:          instante(e.Fecha),
+		Detalle:        e.Detalle,
+	}
+}
+
+type DisponibilidadResponse struct {
+	Fecha    string   `json:"fecha"`
+	Horarios []string `json:"horarios"`
+}
+
+func DisponibilidadFromDomain(d domain.Disponibilidad) *DisponibilidadResponse {
+	horarios := make([]string, 0, len(d.Horarios))
+	for _, h := range d.Horarios {
+		horarios = append(horarios, h.String())
+	}
+	return &DisponibilidadResponse{
+		Fecha:    d.Fecha.Format(time.DateOnly),
+		Horarios: horarios,
 	}
 }
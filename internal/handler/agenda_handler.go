@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/turno"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
+	"github.com/go-chi/chi/v5"
+)
+
+type AgendaHandler struct {
+	s    turno.TurnoService
+	zona *time.Location // para saber qué día es hoy
+}
+
+func NewAgendaHandler(s turno.TurnoService, zona *time.Location) *AgendaHandler {
+	return &AgendaHandler{s: s, zona: zona}
+}
+
+func (h *AgendaHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/semana", h.vista(domain.SemanaDe))
+	r.Get("/mes", h.vista(domain.MesDe))
+	r.Post("/cierre", h.Cerrar)
+}
+
+// Cerrar cierra la agenda de emergencia: bloquea los días y cancela sus turnos.
+func (h *AgendaHandler) Cerrar(w http.ResponseWriter, r *http.Request) {
+	var req dto.CierreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	c, err := req.ToDomain()
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := c.Validate(); err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.s.CerrarAgenda(r.Context(), c)
+	if err != nil {
+		turnoError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.CierreFromDomain(res))
+}
+
+// vista arma el handler de una vista de agenda. ?fecha= (YYYY-MM-DD, hoy si no
+// se envía) elige la semana o el mes; periodo da sus días extremos.
+func (h *AgendaHandler) vista(periodo func(time.Time) (time.Time, time.Time)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fecha, _ := domain.Separar(time.Now(), h.zona)
+		if v := r.URL.Query().Get("fecha"); v != "" {
+			var err error
+			if fecha, err = time.Parse(time.DateOnly, v); err != nil {
+				web.Error(w, http.StatusBadRequest, "formato de fecha invalido, se esperaba YYYY-MM-DD")
+				return
+			}
+		}
+		desde, hasta := periodo(fecha)
+		res, err := h.s.Agenda(r.Context(), desde, hasta)
+		if err != nil {
+			web.Error(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		web.Success(w, http.StatusOK, dto.AgendaFromDomain(res))
+	}
+}
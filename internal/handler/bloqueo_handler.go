@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/bloqueo"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
+	"github.com/go-chi/chi/v5"
+)
+
+type BloqueoHandler struct {
+	s bloqueo.BloqueoService
+}
+
+func NewBloqueoHandler(s bloqueo.BloqueoService) *BloqueoHandler {
+	return &BloqueoHandler{s: s}
+}
+
+func (h *BloqueoHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Put("/{id}", h.Update)
+	r.Get("/{id}", h.GetByID)
+	r.Get("/", h.GetAll) //GET /bloqueo
+	r.Delete("/{id}", h.Delete)
+}
+
+func (h *BloqueoHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.BloqueoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	b, err := req.ToDomain()
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.s.Create(r.Context(), b)
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	web.Success(w, http.StatusCreated, dto.BloqueoFromDomain(res))
+}
+
+func (h *BloqueoHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	var req dto.BloqueoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	b, err := req.ToDomain()
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if id != b.ID {
+		web.Error(w, http.StatusBadRequest, "id in url does not match id in body")
+		return
+	}
+	res, err := h.s.Update(r.Context(), b)
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.BloqueoFromDomain(res))
+}
+
+func (h *BloqueoHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			web.Error(w, http.StatusNotFound, "bloqueo no encontrado")
+			return
+		}
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.BloqueoFromDomain(res))
+}
+
+func (h *BloqueoHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetAll(r.Context())
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	bloqueoSlice := make([]any, 0, len(res))
+	for _, b := range res {
+		bloqueoSlice = append(bloqueoSlice, dto.BloqueoFromDomain(b))
+	}
+	web.Success(w, http.StatusOK, bloqueoSlice)
+}
+
+func (h *BloqueoHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	if err := h.s.Delete(r.Context(), id); err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,21 +1,27 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/cliente"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/turno"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
 	"github.com/go-chi/chi/v5"
 )
 
 type ClienteHandler struct {
-	s cliente.ClienteService
+	s      cliente.ClienteService
+	turnos turno.TurnoService
 }
 
-func NewClienteHandler(s cliente.ClienteService) *ClienteHandler {
-	return &ClienteHandler{s: s}
+func NewClienteHandler(s cliente.ClienteService, turnos turno.TurnoService) *ClienteHandler {
+	return &ClienteHandler{s: s, turnos: turnos}
 }
 
 func (h *ClienteHandler) RegisterRoutes(r chi.Router) {
@@ -23,7 +29,14 @@ func (h *ClienteHandler) RegisterRoutes(r chi.Router) {
 	r.Put("/{id}", h.Update)
 	r.Get("/{id}", h.GetByID)
 	r.Get("/", h.GetAll) //GET /cliente
+	r.Get("/duplicados", h.Duplicados)
 	r.Delete("/{id}", h.Delete)
+	r.Delete("/{id}/restriccion", h.LevantarRestriccion)
+	r.Get("/{id}/turnos", h.Turnos)
+	r.Post("/{id}/fusionar", h.Fusionar)
+	r.Get("/{id}/auditoria", h.Auditoria)
+	r.Get("/{id}/exportacion", h.Exportar)
+	r.Post("/{id}/anonimizar", h.Anonimizar)
 }
 
 func (h *ClienteHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -40,7 +53,7 @@ func (h *ClienteHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	res, err := h.s.Create(r.Context(), c)
 	if err != nil {
-		web.Error(w, http.StatusInternalServerError, err.Error())
+		clienteError(w, err)
 		return
 	}
 
@@ -81,7 +94,7 @@ func (h *ClienteHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	res, err := h.s.Update(r.Context(), c)
 	if err != nil {
-		web.Error(w, http.StatusInternalServerError, err.Error())
+		clienteError(w, err)
 		return
 	}
 	web.Success(w, http.StatusOK, dto.ClienteFromDomain(res))
@@ -102,8 +115,27 @@ func (h *ClienteHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	web.Success(w, http.StatusOK, dto.ClienteFromDomain(res))
 }
 
+// GetAll lista todos los clientes o, con ?q=, busca por nombre o teléfono.
+// ?limite= acota la cantidad de resultados de la búsqueda.
 func (h *ClienteHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	res, err := h.s.GetAll(r.Context())
+	var res []*domain.Cliente
+	var err error
+	if q := r.URL.Query(); q.Has("q") {
+		limite := 0
+		if v := q.Get("limite"); v != "" {
+			if limite, err = strconv.Atoi(v); err != nil {
+				web.Error(w, http.StatusBadRequest, "limite inválido")
+				return
+			}
+		}
+		res, err = h.s.Search(r.Context(), q.Get("q"), limite)
+	} else {
+		res, err = h.s.GetAll(r.Context())
+	}
+	if errors.Is(err, domain.ErrBusquedaCorta) {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		web.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -123,6 +155,14 @@ func (h *ClienteHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.s.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			web.Error(w, http.StatusNotFound, "cliente no encontrado")
+			return
+		}
+		if errors.Is(err, domain.ErrClienteConTurnos) {
+			web.Error(w, http.StatusConflict, err.Error())
+			return
+		}
 		web.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -130,6 +170,162 @@ func (h *ClienteHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// LevantarRestriccion lo usa el administrador para habilitar de nuevo a un
+// cliente bloqueado o que debía dejar seña por sus ausencias.
+func (h *ClienteHandler) LevantarRestriccion(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	res, err := h.s.LevantarRestriccion(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			web.Error(w, http.StatusNotFound, "cliente no encontrado")
+			return
+		}
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.ClienteFromDomain(res))
+}
+
+// Turnos devuelve todos los turnos del cliente, pasados y futuros, con el
+// resumen de sus visitas.
+func (h *ClienteHandler) Turnos(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	if _, err := h.s.GetByID(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			web.Error(w, http.StatusNotFound, "cliente no encontrado")
+			return
+		}
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	res, err := h.turnos.HistorialCliente(r.Context(), id)
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.HistorialClienteFromDomain(res))
+}
+
+// Duplicados lista los pares de clientes que pueden ser la misma persona.
+func (h *ClienteHandler) Duplicados(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.Duplicados(r.Context())
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pares := make([]*dto.PosibleDuplicadoResponse, 0, len(res))
+	for _, p := range res {
+		pares = append(pares, dto.PosibleDuplicadoFromDomain(p))
+	}
+	web.Success(w, http.StatusOK, pares)
+}
+
+// Fusionar pasa todo lo del cliente duplicadoID al de la URL y borra el duplicado.
+func (h *ClienteHandler) Fusionar(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	var req dto.FusionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.DuplicadoID == "" {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	res, err := h.s.Fusionar(r.Context(), id, req.DuplicadoID)
+	if err != nil {
+		if errors.Is(err, domain.ErrFusionMismoCliente) {
+			web.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		clienteError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.FusionFromDomain(res))
+}
+
+// Auditoria devuelve las operaciones registradas sobre el cliente.
+func (h *ClienteHandler) Auditoria(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	res, err := h.s.Auditoria(r.Context(), id)
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	entradas := make([]*dto.EntradaAuditoriaResponse, 0, len(res))
+	for _, e := range res {
+		entradas = append(entradas, dto.EntradaAuditoriaFromDomain(e))
+	}
+	web.Success(w, http.StatusOK, entradas)
+}
+
+// Exportar devuelve todo lo que se guarda del cliente (Ley 25.326).
+func (h *ClienteHandler) Exportar(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	res, err := h.s.Exportar(r.Context(), id)
+	if err != nil {
+		clienteError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.ExportacionFromDomain(res))
+}
+
+// Anonimizar borra los datos personales del cliente y conserva sus turnos.
+func (h *ClienteHandler) Anonimizar(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	res, err := h.s.Anonimizar(r.Context(), id)
+	if err != nil {
+		clienteError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.ClienteFromDomain(res))
+}
+
+// clienteError responde según el error al guardar un cliente. Si el teléfono
+// ya es de otro cliente, se lo devuelve en los detalles para que se pueda usar
+// ese en lugar de cargarlo de nuevo.
+func clienteError(w http.ResponseWriter, err error) {
+	var duplicado *domain.TelefonoDuplicadoError
+	if errors.As(err, &duplicado) {
+		web.ErrorWithDetails(w, http.StatusConflict, err.Error(),
+			map[string]*dto.ClienteResponse{"cliente": dto.ClienteFromDomain(duplicado.Cliente)})
+		return
+	}
+	if errors.Is(err, domain.ErrTelefonoInvalido) {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrClienteAnonimizado) || errors.Is(err, domain.ErrTurnosPorVenir) {
+		web.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		web.Error(w, http.StatusNotFound, "cliente no encontrado")
+		return
+	}
+	web.Error(w, http.StatusInternalServerError, err.Error())
+}
+
 /*
 `http.ResponseWriter` y `*http.Request` son los componentes centrales en un handler HTTP en Go:
 
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/turno"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
+	"github.com/go-chi/chi/v5"
+)
+
+// EsperaHandler expone la lista de espera, que vive dentro del servicio de turnos.
+type EsperaHandler struct {
+	s turno.TurnoService
+}
+
+func NewEsperaHandler(s turno.TurnoService) *EsperaHandler {
+	return &EsperaHandler{s: s}
+}
+
+func (h *EsperaHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Get("/", h.GetAll) //GET /espera
+	r.Get("/{id}", h.GetByID)
+	r.Delete("/{id}", h.Delete)
+	r.Post("/oferta/{id}/aceptar", h.AceptarOferta)
+	r.Post("/oferta/{id}/rechazar", h.RechazarOferta)
+}
+
+func (h *EsperaHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.EsperaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	e, err := req.ToDomain()
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.s.AnotarEnEspera(r.Context(), e)
+	if err != nil {
+		esperaError(w, err)
+		return
+	}
+	web.Success(w, http.StatusCreated, dto.EsperaFromDomain(res))
+}
+
+func (h *EsperaHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.ListaEspera(r.Context())
+	if err != nil {
+		esperaError(w, err)
+		return
+	}
+	esperaSlice := make([]any, 0, len(res))
+	for _, e := range res {
+		esperaSlice = append(esperaSlice, dto.EsperaFromDomain(e))
+	}
+	web.Success(w, http.StatusOK, esperaSlice)
+}
+
+func (h *EsperaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetEspera(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		esperaError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.EsperaFromDomain(res))
+}
+
+func (h *EsperaHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.s.RetirarDeEspera(r.Context(), chi.URLParam(r, "id")); err != nil {
+		esperaError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *EsperaHandler) AceptarOferta(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.AceptarOferta(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		esperaError(w, err)
+		return
+	}
+	web.Success(w, http.StatusCreated, dto.TurnoFromDomain(res))
+}
+
+func (h *EsperaHandler) RechazarOferta(w http.ResponseWriter, r *http.Request) {
+	if err := h.s.RechazarOferta(r.Context(), chi.URLParam(r, "id")); err != nil {
+		esperaError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func esperaError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrOfertaVencida) {
+		web.Error(w, http.StatusGone, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrOfertaCerrada) {
+		web.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		web.Error(w, http.StatusNotFound, "no encontrado")
+		return
+	}
+	// al aceptar una oferta se reserva un turno, con los mismos errores posibles
+	turnoError(w, err)
+}
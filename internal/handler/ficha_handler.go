@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/ficha"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
+	"github.com/go-chi/chi/v5"
+)
+
+// FichaHandler atiende la ficha técnica de un cliente, bajo /cliente/{id}/ficha.
+type FichaHandler struct {
+	s ficha.FichaService
+}
+
+func NewFichaHandler(s ficha.FichaService) *FichaHandler {
+	return &FichaHandler{s: s}
+}
+
+func (h *FichaHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Put("/{entradaID}", h.Update)
+	r.Get("/{entradaID}", h.GetByID)
+	r.Get("/", h.GetByCliente) //GET /cliente/{id}/ficha
+	r.Delete("/{entradaID}", h.Delete)
+}
+
+func (h *FichaHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.FichaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	e, err := req.ToDomain(chi.URLParam(r, "id"))
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.s.Create(r.Context(), e)
+	if err != nil {
+		fichaError(w, err)
+		return
+	}
+	web.Success(w, http.StatusCreated, dto.FichaFromDomain(res))
+}
+
+func (h *FichaHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.FichaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	e, err := req.ToDomain(chi.URLParam(r, "id"))
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if e.ID != chi.URLParam(r, "entradaID") {
+		web.Error(w, http.StatusBadRequest, "id in url does not match id in body")
+		return
+	}
+	res, err := h.s.Update(r.Context(), e)
+	if err != nil {
+		fichaError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.FichaFromDomain(res))
+}
+
+func (h *FichaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetByID(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "entradaID"))
+	if err != nil {
+		fichaError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.FichaFromDomain(res))
+}
+
+func (h *FichaHandler) GetByCliente(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetByCliente(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	entradas := make([]*dto.FichaResponse, 0, len(res))
+	for _, e := range res {
+		entradas = append(entradas, dto.FichaFromDomain(e))
+	}
+	web.Success(w, http.StatusOK, entradas)
+}
+
+func (h *FichaHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.s.Delete(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "entradaID")); err != nil {
+		fichaError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func fichaError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		web.Error(w, http.StatusNotFound, "cliente o entrada de la ficha no encontrados")
+		return
+	}
+	if errors.Is(err, domain.ErrTurnoDeOtroCliente) {
+		web.Error(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	web.Error(w, http.StatusBadRequest, err.Error())
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/horario"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
+	"github.com/go-chi/chi/v5"
+)
+
+type HorarioHandler struct {
+	s horario.HorarioService
+}
+
+func NewHorarioHandler(s horario.HorarioService) *HorarioHandler {
+	return &HorarioHandler{s: s}
+}
+
+func (h *HorarioHandler) RegisterRoutes(r chi.Router) {
+	r.Get("/", h.GetAll) //GET /horario
+	r.Get("/{dia}", h.GetByDia)
+	r.Put("/{dia}", h.Update)
+}
+
+func (h *HorarioHandler) Update(w http.ResponseWriter, r *http.Request) {
+	dia, err := domain.ParseDiaSemana(chi.URLParam(r, "dia"))
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req dto.HorarioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	hl, err := req.ToDomain(dia)
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.s.Update(r.Context(), hl)
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.HorarioFromDomain(res))
+}
+
+func (h *HorarioHandler) GetByDia(w http.ResponseWriter, r *http.Request) {
+	dia, err := domain.ParseDiaSemana(chi.URLParam(r, "dia"))
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.s.GetByDia(r.Context(), dia)
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.HorarioFromDomain(res))
+}
+
+func (h *HorarioHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetAll(r.Context())
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	horarioSlice := make([]any, 0, len(res))
+	for _, hl := range res {
+		horarioSlice = append(horarioSlice, dto.HorarioFromDomain(hl))
+	}
+	web.Success(w, http.StatusOK, horarioSlice)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/servicio"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
+	"github.com/go-chi/chi/v5"
+)
+
+type ServicioHandler struct {
+	s servicio.ServicioService
+}
+
+func NewServicioHandler(s servicio.ServicioService) *ServicioHandler {
+	return &ServicioHandler{s: s}
+}
+
+func (h *ServicioHandler) RegisterRoutes(r chi.Router) {
+	r.Post("/", h.Create)
+	r.Put("/{id}", h.Update)
+	r.Get("/{id}", h.GetByID)
+	r.Get("/", h.GetAll) //GET /servicio
+	r.Delete("/{id}", h.Delete)
+}
+
+func (h *ServicioHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.ServicioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	res, err := h.s.Create(r.Context(), req.ToDomain())
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	web.Success(w, http.StatusCreated, dto.ServicioFromDomain(res))
+}
+
+func (h *ServicioHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	var req dto.ServicioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s := req.ToDomain()
+	if id != s.ID {
+		web.Error(w, http.StatusBadRequest, "id in url does not match id in body")
+		return
+	}
+	res, err := h.s.Update(r.Context(), s)
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.ServicioFromDomain(res))
+}
+
+func (h *ServicioHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	res, err := h.s.GetByID(r.Context(), id)
+	if err != nil {
+		web.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	web.Success(w, http.StatusOK, dto.ServicioFromDomain(res))
+}
+
+func (h *ServicioHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetAll(r.Context())
+	if err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	servicioSlice := make([]any, 0, len(res))
+	for _, s := range res {
+		servicioSlice = append(servicioSlice, dto.ServicioFromDomain(s))
+	}
+	web.Success(w, http.StatusOK, servicioSlice)
+}
+
+func (h *ServicioHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	if err := h.s.Delete(r.Context(), id); err != nil {
+		web.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
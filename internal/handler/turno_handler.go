@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/turno"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/pkg/web"
@@ -13,19 +18,29 @@ import (
 )
 
 type TurnoHandler struct {
-	s turno.TurnoService
+	s          turno.TurnoService
+	tokenAdmin string // vacío: nadie puede pasarse del límite de reservas
 }
 
-func NewTurnoHandler(s turno.TurnoService) *TurnoHandler {
-	return &TurnoHandler{s: s}
+func NewTurnoHandler(s turno.TurnoService, tokenAdmin string) *TurnoHandler {
+	return &TurnoHandler{s: s, tokenAdmin: tokenAdmin}
 }
 
 func (h *TurnoHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/", h.Create)
+	r.Post("/serie", h.CrearSerie)
+	r.Get("/serie/{id}", h.GetSerie)
 	r.Put("/{id}", h.Update)
-	r.Get("/{fecha}", h.GetByFecha)
-	r.Get("/", h.GetAll) //GET /turno
+	r.Get("/disponibles", h.Disponibles)
+	r.Get("/", h.GetAll)
+	r.Get("/{id}", h.GetByID)
 	r.Delete("/{id}", h.Delete)
+	r.Get("/{id}/historial", h.Historial)
+	r.Post("/{id}/confirmar", h.accion(h.s.Confirmar))
+	r.Post("/{id}/cancelar", h.Cancelar)
+	r.Post("/{id}/reprogramar", h.Reprogramar)
+	r.Post("/{id}/completar", h.accion(h.s.Completar))
+	r.Post("/{id}/ausente", h.accion(h.s.MarcarAusente))
 }
 
 func (h *TurnoHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -40,9 +55,14 @@ func (h *TurnoHandler) Create(w http.ResponseWriter, r *http.Request) {
 		web.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	res, err := h.s.Create(r.Context(), t)
+	ctx, err := h.contextoReserva(r)
 	if err != nil {
-		web.Error(w, http.StatusInternalServerError, err.Error())
+		web.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+	res, err := h.s.Create(ctx, t)
+	if err != nil {
+		turnoError(w, err)
 		return
 	}
 
@@ -65,56 +85,178 @@ func (h *TurnoHandler) Update(w http.ResponseWriter, r *http.Request) {
 		web.Error(w, http.StatusBadRequest, "id in url does not match id in request body")
 		return
 	}
-	res, err := h.s.Update(r.Context(), t)
+	alcance, err := domain.ParseAlcanceSerie(r.URL.Query().Get("alcance"))
 	if err != nil {
-		web.Error(w, http.StatusInternalServerError, err.Error())
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx, err := h.contextoReserva(r)
+	if err != nil {
+		web.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if alcance != domain.SoloEste {
+		res, err := h.s.ActualizarSerie(ctx, t, alcance)
+		if err != nil {
+			turnoError(w, err)
+			return
+		}
+		web.Success(w, http.StatusOK, turnosResponse(res))
+		return
+	}
+	res, err := h.s.Update(ctx, t)
+	if err != nil {
+		turnoError(w, err)
 		return
 	}
 	web.Success(w, http.StatusOK, dto.TurnoFromDomain(res))
 }
 
-func (h *TurnoHandler) GetByFecha(w http.ResponseWriter, r *http.Request) {
-	fecha := chi.URLParam(r, "fecha")
-	if fecha == "" {
-		web.Error(w, http.StatusBadRequest, "fecha is required")
+// CrearSerie recibe los mismos campos que un turno más la regla, por ejemplo
+// "rrule": "FREQ=WEEKLY;INTERVAL=3;COUNT=6". El turno del cuerpo es el primero.
+func (h *TurnoHandler) CrearSerie(w http.ResponseWriter, r *http.Request) {
+	var req dto.SerieRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
-
-	decodedFecha, err := url.QueryUnescape(fecha)
+	regla, err := domain.ParseRRule(req.RRule)
 	if err != nil {
-		web.Error(w, http.StatusBadRequest, "invalid fecha format")
+		web.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	fechaparsed, err := time.Parse("2006/01/02", decodedFecha)
+	t, err := h.s.ToDomain(r.Context(), &req.TurnoRequest)
 	if err != nil {
-		web.Error(w, http.StatusBadRequest, "formato de fecha invalido")
+		web.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	res, err := h.s.GetByFecha(r.Context(), fechaparsed)
+	ctx, err := h.contextoReserva(r)
 	if err != nil {
-		web.Error(w, http.StatusNotFound, err.Error())
+		web.Error(w, http.StatusForbidden, err.Error())
 		return
 	}
-	turnoSlice := make([]any, 0, len(res))
-	for _, t := range res {
-		turnoSlice = append(turnoSlice, dto.TurnoFromDomain(t))
+	res, err := h.s.CrearSerie(ctx, t, regla)
+	if err != nil {
+		turnoError(w, err)
+		return
 	}
+	web.Success(w, http.StatusCreated, dto.SerieFromDomain(res))
+}
+
+func (h *TurnoHandler) GetSerie(w http.ResponseWriter, r *http.Request) {
+	res, err := h.s.GetSerie(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		turnoError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.SerieFromDomain(res))
+}
 
-	web.Success(w, http.StatusOK, turnoSlice)
+func (h *TurnoHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	res, err := h.s.GetByID(r.Context(), id)
+	if err != nil {
+		turnoError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.TurnoFromDomain(res))
 }
 
+// GetAll responde GET /turno. Acepta desde y hasta (YYYY-MM-DD, inclusive),
+// clienteID y estado (repetible); los que no se envían no filtran.
 func (h *TurnoHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	res, err := h.s.GetAll(r.Context())
+	q := r.URL.Query()
+	var f domain.FiltroTurnos
+	var err error
+	if v := q.Get("desde"); v != "" {
+		if f.Desde, err = time.Parse(time.DateOnly, v); err != nil {
+			web.Error(w, http.StatusBadRequest, "formato de fecha invalido, se esperaba YYYY-MM-DD")
+			return
+		}
+	}
+	if v := q.Get("hasta"); v != "" {
+		if f.Hasta, err = time.Parse(time.DateOnly, v); err != nil {
+			web.Error(w, http.StatusBadRequest, "formato de fecha invalido, se esperaba YYYY-MM-DD")
+			return
+		}
+	}
+	f.ClienteID = q.Get("clienteID")
+	if f.Estados, err = parseEstados(r); err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := f.Validate(); err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.s.Buscar(r.Context(), f)
 	if err != nil {
 		web.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	turnoSlice := make([]any, 0, len(res))
-	for _, t := range res {
-		turnoSlice = append(turnoSlice, dto.TurnoFromDomain(t))
+	web.Success(w, http.StatusOK, turnosResponse(res))
+}
+
+// Disponibles responde GET /turno/disponibles. Acepta fecha o desde/hasta
+// (YYYY-MM-DD), duracion en minutos o uno o más servicioID, y opcionalmente
+// preferencia (Mañana, Tarde, Noche) e intervalo de la grilla en minutos.
+func (h *TurnoHandler) Disponibles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var c domain.ConsultaDisponibilidad
+	var err error
+
+	desde, hasta := q.Get("desde"), q.Get("hasta")
+	if fecha := q.Get("fecha"); fecha != "" {
+		desde, hasta = fecha, fecha
+	}
+	if c.Desde, err = time.Parse(time.DateOnly, desde); err != nil {
+		web.Error(w, http.StatusBadRequest, "formato de fecha invalido, se esperaba YYYY-MM-DD")
+		return
+	}
+	if c.Hasta, err = time.Parse(time.DateOnly, hasta); err != nil {
+		web.Error(w, http.StatusBadRequest, "formato de fecha invalido, se esperaba YYYY-MM-DD")
+		return
+	}
+	if v := q.Get("duracion"); v != "" {
+		minutos, err := strconv.Atoi(v)
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "duracion invalida")
+			return
+		}
+		c.Duracion = time.Duration(minutos) * time.Minute
+	}
+	if v := q.Get("intervalo"); v != "" {
+		minutos, err := strconv.Atoi(v)
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, "intervalo invalido")
+			return
+		}
+		c.Intervalo = time.Duration(minutos) * time.Minute
+	}
+	if v := q.Get("preferencia"); v != "" {
+		p, err := domain.ParsePreferenciaHoraria(v)
+		if err != nil {
+			web.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Preferencia = &p
+	}
+	c.ServicioIDs = q["servicioID"]
+
+	res, err := h.s.Disponibles(r.Context(), c)
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	disponibles := make([]any, 0, len(res))
+	for _, d := range res {
+		disponibles = append(disponibles, dto.DisponibilidadFromDomain(d))
 	}
-	web.Success(w, http.StatusOK, turnoSlice)
+	web.Success(w, http.StatusOK, disponibles)
 }
 
 func (h *TurnoHandler) Delete(w http.ResponseWriter, r *http.Request) {
@@ -129,3 +271,178 @@ func (h *TurnoHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// accion arma el handler de los endpoints POST /turno/{id}/<accion> que solo
+// cambian el estado del turno.
+func (h *TurnoHandler) accion(fn func(ctx context.Context, id string) (*domain.Turno, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		if id == "" {
+			web.Error(w, http.StatusBadRequest, "id is required")
+			return
+		}
+		res, err := fn(r.Context(), id)
+		if err != nil {
+			turnoError(w, err)
+			return
+		}
+		web.Success(w, http.StatusOK, dto.TurnoFromDomain(res))
+	}
+}
+
+// Cancelar espera un cuerpo {"origen": "cliente"|"negocio", "motivo": "..."}.
+// Con ?alcance=siguientes o ?alcance=todos cancela también otros turnos de la serie.
+func (h *TurnoHandler) Cancelar(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	var req dto.CancelacionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	c, err := req.ToDomain()
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	alcance, err := domain.ParseAlcanceSerie(r.URL.Query().Get("alcance"))
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if alcance != domain.SoloEste {
+		res, err := h.s.CancelarSerie(r.Context(), id, alcance, c)
+		if err != nil {
+			turnoError(w, err)
+			return
+		}
+		web.Success(w, http.StatusOK, turnosResponse(res))
+		return
+	}
+	res, err := h.s.Cancelar(r.Context(), id, c)
+	if err != nil {
+		turnoError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.TurnoFromDomain(res))
+}
+
+// Reprogramar espera un cuerpo {"fecha": "2025/06/10", "hora": "15:00"}.
+func (h *TurnoHandler) Reprogramar(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	var req dto.ReprogramacionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		web.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	fecha, hora, err := req.ToDomain()
+	if err != nil {
+		web.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx, err := h.contextoReserva(r)
+	if err != nil {
+		web.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+	res, err := h.s.Reprogramar(ctx, id, fecha, hora)
+	if err != nil {
+		turnoError(w, err)
+		return
+	}
+	web.Success(w, http.StatusOK, dto.TurnoFromDomain(res))
+}
+
+func (h *TurnoHandler) Historial(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		web.Error(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	res, err := h.s.Historial(r.Context(), id)
+	if err != nil {
+		turnoError(w, err)
+		return
+	}
+	eventos := make([]any, 0, len(res))
+	for _, e := range res {
+		eventos = append(eventos, dto.EventoTurnoFromDomain(e))
+	}
+	web.Success(w, http.StatusOK, eventos)
+}
+
+// parseEstados lee los parámetros ?estado= repetibles de la query.
+func parseEstados(r *http.Request) ([]domain.EstadoTurno, error) {
+	var estados []domain.EstadoTurno
+	for _, v := range r.URL.Query()["estado"] {
+		e, err := domain.ParseEstadoTurno(v)
+		if err != nil {
+			return nil, err
+		}
+		estados = append(estados, e)
+	}
+	return estados, nil
+}
+
+func turnosResponse(turnos []*domain.Turno) []any {
+	res := make([]any, 0, len(turnos))
+	for _, t := range turnos {
+		res = append(res, dto.TurnoFromDomain(t))
+	}
+	return res
+}
+
+var errSoloAdmin = errors.New("solo el administrador puede pasarse del límite de reservas")
+
+// contextoReserva deja pasar por encima del límite de turnos por cliente los
+// pedidos que lo piden con ?excederLimite=true, siempre que traigan el token
+// del administrador en X-Admin-Token.
+func (h *TurnoHandler) contextoReserva(r *http.Request) (context.Context, error) {
+	exceder, _ := strconv.ParseBool(r.URL.Query().Get("excederLimite"))
+	if !exceder {
+		return r.Context(), nil
+	}
+	token := r.Header.Get("X-Admin-Token")
+	if h.tokenAdmin == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.tokenAdmin)) != 1 {
+		return nil, errSoloAdmin
+	}
+	return turno.PermitirExcederLimite(r.Context()), nil
+}
+
+// turnoError traduce los errores del servicio de turnos al código HTTP que corresponde.
+func turnoError(w http.ResponseWriter, err error) {
+	var conflicto *domain.ConflictoTurnoError
+	if errors.As(err, &conflicto) {
+		web.ErrorWithDetails(w, http.StatusConflict, err.Error(), map[string][]string{"turnos": conflicto.IDs})
+		return
+	}
+	if errors.Is(err, domain.ErrClienteBloqueado) || errors.Is(err, domain.ErrClienteAnonimizado) {
+		web.Error(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrFueraDeHorario) || errors.Is(err, domain.ErrAgendaBloqueada) ||
+		errors.Is(err, domain.ErrCancelacionTardia) ||
+		errors.Is(err, domain.ErrSenaRequerida) ||
+		errors.Is(err, domain.ErrTurnoPasado) || errors.Is(err, domain.ErrAnticipacionMinima) ||
+		errors.Is(err, domain.ErrAnticipacionExcedida) || errors.Is(err, domain.ErrLimiteReservas) ||
+		errors.Is(err, domain.ErrClienteDeSerie) {
+		web.Error(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrTransicionInvalida) || errors.Is(err, domain.ErrLugarOfrecido) {
+		web.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		web.Error(w, http.StatusNotFound, "turno no encontrado")
+		return
+	}
+	web.Error(w, http.StatusInternalServerError, err.Error())
+}
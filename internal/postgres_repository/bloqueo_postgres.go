@@ -0,0 +1,101 @@
+package postgresrepository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+const columnasBloqueo = `id, fecha, desde, hasta, motivo, semanal, fin_repeticion`
+
+type BloqueoPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewBloqueoPostgresRepository(db *sql.DB) *BloqueoPostgresRepository {
+	return &BloqueoPostgresRepository{db: db}
+}
+
+func (r *BloqueoPostgresRepository) CreateOrUpdate(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error) {
+	if err := guardarBloqueo(ctx, r.db, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *BloqueoPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Bloqueo, error) {
+	return scanBloqueo(r.db.QueryRowContext(ctx,
+		`SELECT `+columnasBloqueo+` FROM bloqueo WHERE id = $1`, id))
+}
+
+func (r *BloqueoPostgresRepository) GetAll(ctx context.Context) ([]*domain.Bloqueo, error) {
+	return r.listar(ctx, `SELECT `+columnasBloqueo+` FROM bloqueo ORDER BY fecha, desde`)
+}
+
+func (r *BloqueoPostgresRepository) GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Bloqueo, error) {
+	return r.listar(ctx,
+		`SELECT `+columnasBloqueo+`
+		FROM bloqueo
+		WHERE (NOT semanal AND fecha BETWEEN $1 AND $2)
+		OR (semanal AND fecha <= $2 AND (fin_repeticion IS NULL OR fin_repeticion >= $1))
+		ORDER BY fecha, desde`, desde, hasta)
+}
+
+func (r *BloqueoPostgresRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM bloqueo WHERE id = $1`, id)
+	return err
+}
+
+func (r *BloqueoPostgresRepository) listar(ctx context.Context, query string, args ...any) ([]*domain.Bloqueo, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bloqueos []*domain.Bloqueo
+	for rows.Next() {
+		b, err := scanBloqueo(rows)
+		if err != nil {
+			return nil, err
+		}
+		bloqueos = append(bloqueos, b)
+	}
+	return bloqueos, rows.Err()
+}
+
+func scanBloqueo(s scanner) (*domain.Bloqueo, error) {
+	var b domain.Bloqueo
+	var desdeStr, hastaStr string
+	var fin sql.NullTime
+	if err := s.Scan(&b.ID, &b.Fecha, &desdeStr, &hastaStr, &b.Motivo, &b.Semanal, &fin); err != nil {
+		return nil, err
+	}
+	var err error
+	if b.Desde, err = domain.ParseTimeOfDay(desdeStr); err != nil {
+		return nil, err
+	}
+	if b.Hasta, err = domain.ParseFinDeRango(hastaStr); err != nil {
+		return nil, err
+	}
+	b.FinRepeticion = fin.Time
+	return &b, nil
+}
+
+func guardarBloqueo(ctx context.Context, db ejecutor, b *domain.Bloqueo) error {
+	fin := sql.NullTime{Time: b.FinRepeticion, Valid: !b.FinRepeticion.IsZero()}
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO bloqueo(id, fecha, desde, hasta, motivo, semanal, fin_repeticion)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7)
+	 ON CONFLICT (id)
+	 DO UPDATE SET fecha = EXCLUDED.fecha,
+	               desde = EXCLUDED.desde,
+	               hasta = EXCLUDED.hasta,
+	               motivo = EXCLUDED.motivo,
+	               semanal = EXCLUDED.semanal,
+	               fin_repeticion = EXCLUDED.fin_repeticion`,
+		b.ID, b.Fecha, b.Desde.String(), b.Hasta.String(), b.Motivo, b.Semanal, fin)
+	return err
+}
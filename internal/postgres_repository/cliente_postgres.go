@@ -3,8 +3,12 @@ package postgresrepository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/lib/pq"
 )
 
 type ClientePostgresRepository struct {
@@ -24,37 +28,85 @@ func (r *ClientePostgresRepository) CreateOrUpdate(ctx context.Context, c *domai
 	               telefono = EXCLUDED.telefono,
 	               preferenciahoraria = EXCLUDED.preferenciahoraria`,
 		c.ID, c.Nombre, c.Telefono, c.PreferenciaHoraria)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "cliente_telefono_key" {
+		return nil, domain.ErrTelefonoDuplicado
+	}
 	if err != nil {
 		return nil, err
 	}
 	return c, nil
 }
 
+// selectCliente trae además los totales de ausencias y cancelaciones tardías.
+const selectCliente = `SELECT c.id, c.nombre, c.telefono, c.preferenciahoraria,
+	c.restriccion, c.restriccion_levantada_en, c.anonimizado_en,
+	(SELECT count(*) FROM turno t WHERE t.cliente_id = c.id AND t.estado = 'ausente'),
+	(SELECT count(*) FROM turno t WHERE t.cliente_id = c.id AND t.cancelacion_tardia)
+	FROM cliente c`
+
 func (r *ClientePostgresRepository) GetByID(ctx context.Context, id string) (*domain.Cliente, error) {
-	var c domain.Cliente
-	err := r.db.QueryRowContext(ctx,
-		`SELECT id, nombre, telefono, preferenciahoraria FROM cliente WHERE id = $1 `, id).
-		Scan(&c.ID, &c.Nombre, &c.Telefono, &c.PreferenciaHoraria)
+	return scanCliente(r.db.QueryRowContext(ctx, selectCliente+` WHERE c.id = $1`, id))
+}
+
+func (r *ClientePostgresRepository) BuscarPorTelefono(ctx context.Context, telefono string) (*domain.Cliente, error) {
+	c, err := scanCliente(r.db.QueryRowContext(ctx, selectCliente+` WHERE c.telefono = $1`, telefono))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// searchCliente compara el nombre con la consulta sin acentos ni mayúsculas:
+// primero los que empiezan igual, después los que la contienen y al final los
+// parecidos según pg_trgm. Los clientes anonimizados no aparecen. Los dígitos de la consulta, si son al menos 3, se
+// buscan también dentro del teléfono; una coincidencia con el final del
+// teléfono va antes que todo.
+const searchCliente = selectCliente + `,
+	LATERAL (SELECT sin_acentos(lower(c.nombre)) AS nombre,
+		sin_acentos(lower($1)) AS consulta,
+		regexp_replace($1, '\D', '', 'g') AS digitos) b
+	WHERE c.anonimizado_en IS NULL
+	  AND (strpos(b.nombre, b.consulta) > 0
+	   OR b.nombre % b.consulta
+	   OR (length(b.digitos) >= 3 AND strpos(c.telefono, b.digitos) > 0))
+	ORDER BY
+	   CASE
+	       WHEN length(b.digitos) >= 3 AND c.telefono LIKE '%' || b.digitos THEN 0
+	       WHEN strpos(b.nombre, b.consulta) = 1 THEN 1
+	       WHEN strpos(b.nombre, b.consulta) > 0 THEN 2
+	       ELSE 3
+	   END,
+	   similarity(b.nombre, b.consulta) DESC,
+	   c.nombre
+	LIMIT $2`
+
+func (r *ClientePostgresRepository) Search(ctx context.Context, consulta string, limite int) ([]*domain.Cliente, error) {
+	rows, err := r.db.QueryContext(ctx, searchCliente, consulta, limite)
 	if err != nil {
 		return nil, err
 	}
-	return &c, nil
+	defer rows.Close()
+	return scanClientes(rows)
 }
 
 func (r *ClientePostgresRepository) GetAll(ctx context.Context) ([]*domain.Cliente, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, nombre, telefono, preferenciahoraria FROM cliente`)
+	rows, err := r.db.QueryContext(ctx, selectCliente)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	return scanClientes(rows)
+}
 
+func scanClientes(rows *sql.Rows) ([]*domain.Cliente, error) {
 	var clientes []*domain.Cliente
 	for rows.Next() {
-		var c domain.Cliente
-		if err := rows.Scan(&c.ID, &c.Nombre, &c.Telefono, &c.PreferenciaHoraria); err != nil {
+		c, err := scanCliente(rows)
+		if err != nil {
 			return nil, err
 		}
-		clientes = append(clientes, &c)
+		clientes = append(clientes, c)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -62,7 +114,327 @@ func (r *ClientePostgresRepository) GetAll(ctx context.Context) ([]*domain.Clien
 	return clientes, nil
 }
 
+// Delete borra al cliente con su ficha, su lista de espera, sus notificaciones
+// y sus series. Si tiene turnos no borra nada y devuelve ErrClienteConTurnos,
+// para no perder la historia de la agenda.
 func (r *ClientePostgresRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM cliente WHERE id = $1`, id)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var conTurnos bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM turno WHERE cliente_id = $1)`, id).Scan(&conTurnos); err != nil {
+		return err
+	}
+	if conTurnos {
+		return domain.ErrClienteConTurnos
+	}
+	for _, tabla := range []string{"ficha", "lista_espera", "notificacion", "serie"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+tabla+` WHERE cliente_id = $1`, id); err != nil {
+			return err
+		}
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM cliente WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *ClientePostgresRepository) GuardarRestriccion(ctx context.Context, c *domain.Cliente) error {
+	var levantada sql.NullTime
+	if !c.RestriccionLevantada.IsZero() {
+		levantada = sql.NullTime{Time: c.RestriccionLevantada, Valid: true}
+	}
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE cliente SET restriccion = $2, restriccion_levantada_en = $3 WHERE id = $1`,
+		c.ID, c.Restriccion.String(), levantada)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+func scanCliente(s scanner) (*domain.Cliente, error) {
+	var c domain.Cliente
+	var restriccion string
+	var levantada, anonimizado sql.NullTime
+	if err := s.Scan(&c.ID, &c.Nombre, &c.Telefono, &c.PreferenciaHoraria,
+		&restriccion, &levantada, &anonimizado, &c.Ausencias, &c.CancelacionesTardias); err != nil {
+		return nil, err
+	}
+	var err error
+	if c.Restriccion, err = domain.ParseRestriccionCliente(restriccion); err != nil {
+		return nil, err
+	}
+	if levantada.Valid {
+		c.RestriccionLevantada = levantada.Time
+	}
+	if anonimizado.Valid {
+		c.Anonimizado = anonimizado.Time
+	}
+	return &c, nil
+}
+
+func (r *ClientePostgresRepository) NombresSimilares(ctx context.Context, umbral float64) ([]domain.NombresSimilares, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT a.id, b.id, similarity(sin_acentos(lower(a.nombre)), sin_acentos(lower(b.nombre)))
+		FROM cliente a
+		JOIN cliente b ON a.id < b.id
+		WHERE a.anonimizado_en IS NULL AND b.anonimizado_en IS NULL
+		  AND similarity(sin_acentos(lower(a.nombre)), sin_acentos(lower(b.nombre))) >= $1`, umbral)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pares []domain.NombresSimilares
+	for rows.Next() {
+		var p domain.NombresSimilares
+		if err := rows.Scan(&p.IDs[0], &p.IDs[1], &p.Similitud); err != nil {
+			return nil, err
+		}
+		pares = append(pares, p)
+	}
+	return pares, rows.Err()
+}
+
+// tablasDeCliente son las tablas con filas que pertenecen a un cliente y que
+// una fusión pasa al sobreviviente.
+var tablasDeCliente = []string{"turno", "serie", "lista_espera", "notificacion", "ficha"}
+
+func (r *ClientePostgresRepository) Fusionar(ctx context.Context, f *domain.Fusion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	f.Movidos = make(map[string]int64, len(tablasDeCliente))
+	for _, tabla := range tablasDeCliente {
+		res, err := tx.ExecContext(ctx,
+			`UPDATE `+tabla+` SET cliente_id = $1 WHERE cliente_id = $2`, f.Sobreviviente.ID, f.Duplicado.ID)
+		if err != nil {
+			return err
+		}
+		if f.Movidos[tabla], err = res.RowsAffected(); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE cliente SET restriccion = $2 WHERE id = $1`,
+		f.Sobreviviente.ID, f.Sobreviviente.Restriccion.String()); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM cliente WHERE id = $1`, f.Duplicado.ID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := auditar(ctx, tx, f.Auditoria()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ejecutor es lo que tienen en común *sql.DB y *sql.Tx para escribir.
+type ejecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func auditar(ctx context.Context, db ejecutor, e *domain.EntradaAuditoria) error {
+	detalle, err := json.Marshal(e.Detalle)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO auditoria_cliente(id, accion, cliente_id, detalle, realizada_en)
+		VALUES ($1, $2, $3, $4, $5)`,
+		e.ID, e.Accion, e.ClienteID, detalle, e.Fecha)
 	return err
 }
+
+func (r *ClientePostgresRepository) Auditoria(ctx context.Context, clienteID string) ([]*domain.EntradaAuditoria, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, accion, cliente_id, detalle, realizada_en FROM auditoria_cliente
+		WHERE cliente_id = $1 ORDER BY realizada_en DESC`, clienteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entradas []*domain.EntradaAuditoria
+	for rows.Next() {
+		var e domain.EntradaAuditoria
+		var detalle []byte
+		if err := rows.Scan(&e.ID, &e.Accion, &e.ClienteID, &detalle, &e.Fecha); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(detalle, &e.Detalle); err != nil {
+			return nil, err
+		}
+		entradas = append(entradas, &e)
+	}
+	return entradas, rows.Err()
+}
+
+func (r *ClientePostgresRepository) RegistrarAuditoria(ctx context.Context, e *domain.EntradaAuditoria) error {
+	return auditar(ctx, r.db, e)
+}
+
+func (r *ClientePostgresRepository) Exportar(ctx context.Context, id string) (*domain.ExportacionCliente, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	e := &domain.ExportacionCliente{ClienteID: id, Tablas: make(map[string][]map[string]any)}
+	var fila []byte
+	if err := tx.QueryRowContext(ctx, `SELECT to_jsonb(c) FROM cliente c WHERE c.id = $1`, id).Scan(&fila); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(fila, &e.Cliente); err != nil {
+		return nil, err
+	}
+	for tabla, query := range exportaciones {
+		if e.Tablas[tabla], err = filasJSON(ctx, tx, query, id); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// exportaciones tiene, por tabla, la consulta que trae las filas de un cliente
+// como objetos JSON.
+var exportaciones = func() map[string]string {
+	consultas := map[string]string{
+		"auditoria_cliente": `SELECT to_jsonb(a) FROM auditoria_cliente a WHERE a.cliente_id = $1`,
+		"turno_servicio": `SELECT to_jsonb(ts) FROM turno_servicio ts
+			JOIN turno t ON t.id = ts.turno_id WHERE t.cliente_id = $1`,
+		"turno_historial": `SELECT to_jsonb(h) FROM turno_historial h
+			JOIN turno t ON t.id = h.turno_id WHERE t.cliente_id = $1`,
+		"oferta_espera": `SELECT to_jsonb(o) FROM oferta_espera o
+			JOIN lista_espera e ON e.id = o.entrada_id WHERE e.cliente_id = $1`,
+	}
+	for _, tabla := range tablasDeCliente {
+		consultas[tabla] = `SELECT to_jsonb(t) FROM ` + tabla + ` t WHERE t.cliente_id = $1`
+	}
+	return consultas
+}()
+
+// filasJSON junta las filas de una consulta que devuelve un objeto JSON por fila.
+func filasJSON(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]map[string]any, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	filas := []map[string]any{}
+	for rows.Next() {
+		var b []byte
+		var fila map[string]any
+		if err := rows.Scan(&b); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &fila); err != nil {
+			return nil, err
+		}
+		filas = append(filas, fila)
+	}
+	return filas, rows.Err()
+}
+
+// Anonimizar borra los datos personales del cliente en una transacción: su
+// nombre y teléfono, las notas de la ficha, de las cancelaciones y del
+// historial de sus turnos, sus notificaciones y su lista de espera, y los datos
+// del duplicado en las fusiones que lo tuvieron como sobreviviente. Los turnos,
+// sus servicios y las señas quedan para las estadísticas.
+func (r *ClientePostgresRepository) Anonimizar(ctx context.Context, a *domain.Anonimizacion) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// el teléfono es único y obligatorio, así que se reemplaza por uno que no es un número
+	res, err := tx.ExecContext(ctx,
+		`UPDATE cliente SET nombre = $2, telefono = 'anonimizado:' || id, anonimizado_en = $3
+		WHERE id = $1 AND anonimizado_en IS NULL`,
+		a.ClienteID, domain.NombreAnonimizado, a.Fecha)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+
+	a.Borrados = make(map[string]int64)
+	for _, tabla := range []string{"notificacion", "lista_espera"} {
+		if a.Borrados[tabla], err = filasAfectadas(tx.ExecContext(ctx,
+			`DELETE FROM `+tabla+` WHERE cliente_id = $1`, a.ClienteID)); err != nil {
+			return err
+		}
+	}
+	a.Limpiados = make(map[string]int64)
+	limpiezas := map[string]string{
+		"ficha": `UPDATE ficha SET notas = '', alergias = '', tipo_cabello = ''
+			WHERE cliente_id = $1 AND (notas <> '' OR alergias <> '' OR tipo_cabello <> '')`,
+		"turno": `UPDATE turno SET motivo_cancelacion = ''
+			WHERE cliente_id = $1 AND motivo_cancelacion <> ''`,
+		"turno_historial": `UPDATE turno_historial h SET detalle = ''
+			FROM turno t WHERE t.id = h.turno_id AND t.cliente_id = $1 AND h.detalle <> ''`,
+		"auditoria_cliente": `UPDATE auditoria_cliente
+			SET detalle = jsonb_set(detalle, '{duplicado}', (detalle->'duplicado') - 'nombre' - 'telefono')
+			WHERE cliente_id = $1 AND accion = '` + domain.AccionFusion + `'`,
+	}
+	for tabla, query := range limpiezas {
+		if a.Limpiados[tabla], err = filasAfectadas(tx.ExecContext(ctx, query, a.ClienteID)); err != nil {
+			return err
+		}
+	}
+	if err := auditar(ctx, tx, a.Auditoria()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ClientePostgresRepository) TurnosPorVenir(ctx context.Context, clienteID string, desde time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id FROM turno
+		WHERE cliente_id = $1 AND inicio > $2 AND estado = ANY($3)
+		ORDER BY inicio`, clienteID, desde, pq.Array(nombresEstado(domain.EstadosActivos)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func filasAfectadas(res sql.Result, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
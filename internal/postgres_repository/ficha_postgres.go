@@ -0,0 +1,124 @@
+package postgresrepository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/lib/pq"
+)
+
+type FichaPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewFichaPostgresRepository(db *sql.DB) *FichaPostgresRepository {
+	return &FichaPostgresRepository{db: db}
+}
+
+func (r *FichaPostgresRepository) CreateOrUpdate(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error) {
+	// una entrada no cambia de cliente: si el ID ya es de otro, no se actualiza nada
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO ficha(id, cliente_id, turno_id, fecha, marca, formula, oxidante,
+			tiempo_pose, tipo_cabello, alergias, notas)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id)
+		DO UPDATE SET turno_id = EXCLUDED.turno_id,
+		              fecha = EXCLUDED.fecha,
+		              marca = EXCLUDED.marca,
+		              formula = EXCLUDED.formula,
+		              oxidante = EXCLUDED.oxidante,
+		              tiempo_pose = EXCLUDED.tiempo_pose,
+		              tipo_cabello = EXCLUDED.tipo_cabello,
+		              alergias = EXCLUDED.alergias,
+		              notas = EXCLUDED.notas
+		WHERE ficha.cliente_id = EXCLUDED.cliente_id`,
+		e.ID, e.ClienteID, sql.NullString{String: e.TurnoID, Valid: e.TurnoID != ""}, e.Fecha,
+		e.Marca, e.Formula, e.Oxidante, int(e.TiempoPose/time.Minute), e.TipoCabello, e.Alergias, e.Notas)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "ficha_cliente_id_fkey" {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return e, nil
+}
+
+func (r *FichaPostgresRepository) Delete(ctx context.Context, clienteID, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM ficha WHERE cliente_id = $1 AND id = $2`, clienteID, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+const selectFicha = `SELECT id, cliente_id, turno_id, fecha, marca, formula, oxidante,
+	tiempo_pose, tipo_cabello, alergias, notas FROM ficha`
+
+func (r *FichaPostgresRepository) GetByID(ctx context.Context, clienteID, id string) (*domain.EntradaFicha, error) {
+	return scanFicha(r.db.QueryRowContext(ctx, selectFicha+` WHERE cliente_id = $1 AND id = $2`, clienteID, id))
+}
+
+func (r *FichaPostgresRepository) GetByCliente(ctx context.Context, clienteID string) ([]*domain.EntradaFicha, error) {
+	rows, err := r.db.QueryContext(ctx,
+		selectFicha+` WHERE cliente_id = $1 ORDER BY fecha DESC, id`, clienteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entradas []*domain.EntradaFicha
+	for rows.Next() {
+		e, err := scanFicha(rows)
+		if err != nil {
+			return nil, err
+		}
+		entradas = append(entradas, e)
+	}
+	return entradas, rows.Err()
+}
+
+func (r *FichaPostgresRepository) UltimasFormulas(ctx context.Context, clienteIDs []string) (map[string]*domain.EntradaFicha, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT DISTINCT ON (cliente_id) id, cliente_id, turno_id, fecha, marca, formula, oxidante,
+			tiempo_pose, tipo_cabello, alergias, notas
+		FROM ficha
+		WHERE cliente_id = ANY($1) AND formula <> ''
+		ORDER BY cliente_id, fecha DESC, id`, pq.Array(clienteIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	formulas := make(map[string]*domain.EntradaFicha)
+	for rows.Next() {
+		e, err := scanFicha(rows)
+		if err != nil {
+			return nil, err
+		}
+		formulas[e.ClienteID] = e
+	}
+	return formulas, rows.Err()
+}
+
+func scanFicha(s scanner) (*domain.EntradaFicha, error) {
+	var e domain.EntradaFicha
+	var turnoID sql.NullString
+	var pose int
+	if err := s.Scan(&e.ID, &e.ClienteID, &turnoID, &e.Fecha, &e.Marca, &e.Formula, &e.Oxidante,
+		&pose, &e.TipoCabello, &e.Alergias, &e.Notas); err != nil {
+		return nil, err
+	}
+	e.TurnoID = turnoID.String
+	e.TiempoPose = time.Duration(pose) * time.Minute
+	return &e, nil
+}
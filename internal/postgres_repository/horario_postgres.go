@@ -0,0 +1,110 @@
+package postgresrepository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type HorarioLaboralPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewHorarioLaboralPostgresRepository(db *sql.DB) *HorarioLaboralPostgresRepository {
+	return &HorarioLaboralPostgresRepository{db: db}
+}
+
+func (r *HorarioLaboralPostgresRepository) CreateOrUpdate(ctx context.Context, h *domain.HorarioLaboral) (*domain.HorarioLaboral, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM horario_laboral WHERE dia_semana = $1`, int(h.Dia)); err != nil {
+		return nil, err
+	}
+	for _, i := range h.Intervalos {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO horario_laboral(dia_semana, desde, hasta) VALUES ($1, $2, $3)`,
+			int(h.Dia), i.Desde.String(), i.Hasta.String()); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (r *HorarioLaboralPostgresRepository) GetByDia(ctx context.Context, dia time.Weekday) (*domain.HorarioLaboral, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT desde, hasta FROM horario_laboral WHERE dia_semana = $1 ORDER BY desde`, int(dia))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	h := domain.NewHorarioLaboral(dia, nil)
+	for rows.Next() {
+		var desdeStr, hastaStr string
+		if err := rows.Scan(&desdeStr, &hastaStr); err != nil {
+			return nil, err
+		}
+		i, err := parseIntervalo(desdeStr, hastaStr)
+		if err != nil {
+			return nil, err
+		}
+		h.Intervalos = append(h.Intervalos, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// GetAll devuelve los siete días de la semana, empezando por el domingo,
+// incluso los que no tienen franjas cargadas.
+func (r *HorarioLaboralPostgresRepository) GetAll(ctx context.Context) ([]*domain.HorarioLaboral, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT dia_semana, desde, hasta FROM horario_laboral ORDER BY dia_semana, desde`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	semana := make([]*domain.HorarioLaboral, 7)
+	for d := range semana {
+		semana[d] = domain.NewHorarioLaboral(time.Weekday(d), nil)
+	}
+	for rows.Next() {
+		var dia int
+		var desdeStr, hastaStr string
+		if err := rows.Scan(&dia, &desdeStr, &hastaStr); err != nil {
+			return nil, err
+		}
+		i, err := parseIntervalo(desdeStr, hastaStr)
+		if err != nil {
+			return nil, err
+		}
+		semana[dia].Intervalos = append(semana[dia].Intervalos, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return semana, nil
+}
+
+func parseIntervalo(desdeStr, hastaStr string) (domain.IntervaloHorario, error) {
+	desde, err := domain.ParseTimeOfDay(desdeStr)
+	if err != nil {
+		return domain.IntervaloHorario{}, err
+	}
+	hasta, err := domain.ParseTimeOfDay(hastaStr)
+	if err != nil {
+		return domain.IntervaloHorario{}, err
+	}
+	return domain.IntervaloHorario{Desde: desde, Hasta: hasta}, nil
+}
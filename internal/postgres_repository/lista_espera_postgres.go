@@ -0,0 +1,255 @@
+package postgresrepository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/lib/pq"
+)
+
+const columnasEspera = `e.id, e.cliente_id, e.desde, e.hasta, e.preferencia, e.duracion,
+	e.servicio_ids, e.estado, e.creada_en`
+
+const columnasOferta = `o.id, o.entrada_id, o.fecha, o.hora, o.duracion, o.vence_en,
+	o.estado, o.turno_id, o.creada_en`
+
+// ListaEsperaPostgresRepository devuelve la creación de las entradas y de las
+// ofertas y el vencimiento de las ofertas en zona.
+type ListaEsperaPostgresRepository struct {
+	db   *sql.DB
+	zona *time.Location
+}
+
+func NewListaEsperaPostgresRepository(db *sql.DB, zona *time.Location) *ListaEsperaPostgresRepository {
+	return &ListaEsperaPostgresRepository{db: db, zona: zona}
+}
+
+func (r *ListaEsperaPostgresRepository) CreateOrUpdate(ctx context.Context, e *domain.EntradaEspera) (*domain.EntradaEspera, error) {
+	if err := guardarEntrada(ctx, r.db, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *ListaEsperaPostgresRepository) GetByID(ctx context.Context, id string) (*domain.EntradaEspera, error) {
+	e, err := r.scanEntrada(r.db.QueryRowContext(ctx,
+		`SELECT `+columnasEspera+` FROM lista_espera e WHERE e.id = $1`, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cargarOfertas(ctx, []*domain.EntradaEspera{e}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *ListaEsperaPostgresRepository) GetActivas(ctx context.Context) ([]*domain.EntradaEspera, error) {
+	entradas, err := r.listar(ctx,
+		`SELECT `+columnasEspera+`
+		FROM lista_espera e
+		WHERE e.estado IN ('esperando', 'con oferta')
+		ORDER BY e.creada_en`)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.cargarOfertas(ctx, entradas); err != nil {
+		return nil, err
+	}
+	return entradas, nil
+}
+
+func (r *ListaEsperaPostgresRepository) GetEsperando(ctx context.Context, fecha time.Time) ([]*domain.EntradaEspera, error) {
+	return r.listar(ctx,
+		`SELECT `+columnasEspera+`
+		FROM lista_espera e
+		WHERE e.estado = 'esperando'
+		AND $1 BETWEEN e.desde AND e.hasta
+		ORDER BY e.creada_en`, fecha)
+}
+
+func (r *ListaEsperaPostgresRepository) GuardarOferta(ctx context.Context, o *domain.Oferta, e *domain.EntradaEspera) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	turnoID := sql.NullString{String: o.TurnoID, Valid: o.TurnoID != ""}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO oferta_espera(id, entrada_id, fecha, hora, duracion, vence_en, estado, turno_id, creada_en)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id)
+		DO UPDATE SET estado = EXCLUDED.estado,
+		turno_id = EXCLUDED.turno_id`,
+		o.ID, o.EntradaID, o.Fecha, o.Hora.String(), int(o.Duracion/time.Minute), o.VenceEn,
+		o.Estado.String(), turnoID, o.CreadaEn); err != nil {
+		return err
+	}
+	if err := guardarEntrada(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ListaEsperaPostgresRepository) GetOferta(ctx context.Context, id string) (*domain.Oferta, error) {
+	return r.scanOferta(r.db.QueryRowContext(ctx,
+		`SELECT `+columnasOferta+` FROM oferta_espera o WHERE o.id = $1`, id))
+}
+
+func (r *ListaEsperaPostgresRepository) GetOfertasVencidas(ctx context.Context, ahora time.Time) ([]*domain.Oferta, error) {
+	return r.listarOfertas(ctx,
+		`SELECT `+columnasOferta+`
+		FROM oferta_espera o
+		WHERE o.estado = 'pendiente' AND o.vence_en <= $1
+		ORDER BY o.vence_en`, ahora)
+}
+
+func (r *ListaEsperaPostgresRepository) GetOfertasPendientes(ctx context.Context, desde, hasta, ahora time.Time) ([]*domain.Oferta, error) {
+	return r.listarOfertas(ctx,
+		`SELECT `+columnasOferta+`
+		FROM oferta_espera o
+		WHERE o.estado = 'pendiente' AND o.vence_en > $3
+		AND o.fecha BETWEEN $1 AND $2
+		ORDER BY o.fecha, o.hora`, desde, hasta, ahora)
+}
+
+func (r *ListaEsperaPostgresRepository) listarOfertas(ctx context.Context, query string, args ...any) ([]*domain.Oferta, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ofertas []*domain.Oferta
+	for rows.Next() {
+		o, err := r.scanOferta(rows)
+		if err != nil {
+			return nil, err
+		}
+		ofertas = append(ofertas, o)
+	}
+	return ofertas, rows.Err()
+}
+
+func (r *ListaEsperaPostgresRepository) listar(ctx context.Context, query string, args ...any) ([]*domain.EntradaEspera, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entradas []*domain.EntradaEspera
+	for rows.Next() {
+		e, err := r.scanEntrada(rows)
+		if err != nil {
+			return nil, err
+		}
+		entradas = append(entradas, e)
+	}
+	return entradas, rows.Err()
+}
+
+// cargarOfertas completa la oferta pendiente de cada entrada con una sola consulta.
+func (r *ListaEsperaPostgresRepository) cargarOfertas(ctx context.Context, entradas []*domain.EntradaEspera) error {
+	if len(entradas) == 0 {
+		return nil
+	}
+	porID := make(map[string]*domain.EntradaEspera, len(entradas))
+	ids := make([]string, 0, len(entradas))
+	for _, e := range entradas {
+		porID[e.ID] = e
+		ids = append(ids, e.ID)
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+columnasOferta+`
+		FROM oferta_espera o
+		WHERE o.entrada_id = ANY($1) AND o.estado = 'pendiente'`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		o, err := r.scanOferta(rows)
+		if err != nil {
+			return err
+		}
+		porID[o.EntradaID].Oferta = o
+	}
+	return rows.Err()
+}
+
+// execer lo cumplen tanto *sql.DB como *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func guardarEntrada(ctx context.Context, db execer, e *domain.EntradaEspera) error {
+	var preferencia sql.NullInt64
+	if e.Preferencia != nil {
+		preferencia = sql.NullInt64{Int64: int64(*e.Preferencia), Valid: true}
+	}
+	servicioIDs := e.ServicioIDs
+	if servicioIDs == nil {
+		servicioIDs = []string{}
+	}
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO lista_espera(id, cliente_id, desde, hasta, preferencia, duracion, servicio_ids, estado, creada_en)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id)
+		DO UPDATE SET desde = EXCLUDED.desde,
+		hasta = EXCLUDED.hasta,
+		preferencia = EXCLUDED.preferencia,
+		duracion = EXCLUDED.duracion,
+		servicio_ids = EXCLUDED.servicio_ids,
+		estado = EXCLUDED.estado`,
+		e.ID, e.Cliente.ID, e.Desde, e.Hasta, preferencia, int(e.Duracion/time.Minute),
+		pq.Array(servicioIDs), e.Estado.String(), e.CreadaEn)
+	return err
+}
+
+func (r *ListaEsperaPostgresRepository) scanEntrada(s scanner) (*domain.EntradaEspera, error) {
+	var e domain.EntradaEspera
+	var preferencia sql.NullInt64
+	var duracion int
+	var estado string
+	if err := s.Scan(&e.ID, &e.Cliente.ID, &e.Desde, &e.Hasta, &preferencia, &duracion,
+		pq.Array(&e.ServicioIDs), &estado, &e.CreadaEn); err != nil {
+		return nil, err
+	}
+	var err error
+	if e.Estado, err = domain.ParseEstadoEspera(estado); err != nil {
+		return nil, err
+	}
+	if preferencia.Valid {
+		p := domain.PreferenciaHoraria(preferencia.Int64)
+		e.Preferencia = &p
+	}
+	e.Duracion = time.Duration(duracion) * time.Minute
+	e.CreadaEn = e.CreadaEn.In(r.zona)
+	return &e, nil
+}
+
+func (r *ListaEsperaPostgresRepository) scanOferta(s scanner) (*domain.Oferta, error) {
+	var o domain.Oferta
+	var horaStr, estado string
+	var duracion int
+	var turnoID sql.NullString
+	if err := s.Scan(&o.ID, &o.EntradaID, &o.Fecha, &horaStr, &duracion, &o.VenceEn,
+		&estado, &turnoID, &o.CreadaEn); err != nil {
+		return nil, err
+	}
+	var err error
+	if o.Hora, err = domain.ParseTimeOfDay(horaStr); err != nil {
+		return nil, err
+	}
+	if o.Estado, err = domain.ParseEstadoOferta(estado); err != nil {
+		return nil, err
+	}
+	o.Duracion = time.Duration(duracion) * time.Minute
+	o.TurnoID = turnoID.String
+	o.VenceEn = o.VenceEn.In(r.zona)
+	o.CreadaEn = o.CreadaEn.In(r.zona)
+	return &o, nil
+}
@@ -0,0 +1,29 @@
+package postgresrepository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+type NotificacionPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewNotificacionPostgresRepository(db *sql.DB) *NotificacionPostgresRepository {
+	return &NotificacionPostgresRepository{db: db}
+}
+
+func (r *NotificacionPostgresRepository) Create(ctx context.Context, n *domain.Notificacion) error {
+	return guardarNotificacion(ctx, r.db, n)
+}
+
+func guardarNotificacion(ctx context.Context, db ejecutor, n *domain.Notificacion) error {
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO notificacion(id, cliente_id, turno_id, telefono, mensaje, creada_en)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		n.ID, n.ClienteID, sql.NullString{String: n.TurnoID, Valid: n.TurnoID != ""},
+		n.Telefono, n.Mensaje, n.CreadaEn)
+	return err
+}
@@ -0,0 +1,127 @@
+package postgresrepository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/lib/pq"
+)
+
+const columnasServicio = `id, nombre, duracion, precio, activo, margen_antes, margen_despues, fases`
+
+type ServicioPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewServicioPostgresRepository(db *sql.DB) *ServicioPostgresRepository {
+	return &ServicioPostgresRepository{db: db}
+}
+
+func (r *ServicioPostgresRepository) CreateOrUpdate(ctx context.Context, s *domain.Servicio) (*domain.Servicio, error) {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO servicio(id, nombre, duracion, precio, activo, margen_antes, margen_despues, fases)
+	 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	 ON CONFLICT (id)
+	 DO UPDATE SET nombre = EXCLUDED.nombre,
+	               duracion = EXCLUDED.duracion,
+	               precio = EXCLUDED.precio,
+	               activo = EXCLUDED.activo,
+	               margen_antes = EXCLUDED.margen_antes,
+	               margen_despues = EXCLUDED.margen_despues,
+	               fases = EXCLUDED.fases`,
+		s.ID, s.Nombre, int(s.Duracion/time.Minute), s.Precio, s.Activo,
+		minutosNulos(s.MargenAntes), minutosNulos(s.MargenDespues), pq.Array(fasesEnMinutos(s.Fases)))
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *ServicioPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Servicio, error) {
+	return scanServicio(r.db.QueryRowContext(ctx,
+		`SELECT `+columnasServicio+` FROM servicio WHERE id = $1`, id))
+}
+
+func (r *ServicioPostgresRepository) GetAll(ctx context.Context) ([]*domain.Servicio, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+columnasServicio+` FROM servicio ORDER BY nombre`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var servicios []*domain.Servicio
+	for rows.Next() {
+		s, err := scanServicio(rows)
+		if err != nil {
+			return nil, err
+		}
+		servicios = append(servicios, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return servicios, nil
+}
+
+func (r *ServicioPostgresRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM servicio WHERE id = $1`, id)
+	return err
+}
+
+// scanServicio lee las columnas de columnasServicio, en ese orden, seguidas de extra.
+func scanServicio(sc scanner, extra ...any) (*domain.Servicio, error) {
+	var s domain.Servicio
+	var duracion int
+	var antes, despues sql.NullInt64
+	var fases []int64
+	dest := append([]any{&s.ID, &s.Nombre, &duracion, &s.Precio, &s.Activo, &antes, &despues, pq.Array(&fases)}, extra...)
+	if err := sc.Scan(dest...); err != nil {
+		return nil, err
+	}
+	s.Duracion = time.Duration(duracion) * time.Minute
+	s.MargenAntes = minutos(antes)
+	s.MargenDespues = minutos(despues)
+	s.Fases = fasesDeMinutos(fases)
+	return &s, nil
+}
+
+// fasesEnMinutos guarda solo la duración de cada fase: como alternan trabajo y
+// pose empezando por trabajo, la posición dice de qué tipo es.
+func fasesEnMinutos(fases []domain.Fase) []int64 {
+	if len(fases) == 0 {
+		return nil
+	}
+	res := make([]int64, len(fases))
+	for i, f := range fases {
+		res[i] = int64(f.Duracion / time.Minute)
+	}
+	return res
+}
+
+func fasesDeMinutos(minutos []int64) []domain.Fase {
+	if len(minutos) == 0 {
+		return nil
+	}
+	fases := make([]domain.Fase, len(minutos))
+	for i, m := range minutos {
+		fases[i] = domain.Fase{Duracion: time.Duration(m) * time.Minute, Libre: i%2 == 1}
+	}
+	return fases
+}
+
+func minutos(n sql.NullInt64) *time.Duration {
+	if !n.Valid {
+		return nil
+	}
+	d := time.Duration(n.Int64) * time.Minute
+	return &d
+}
+
+func minutosNulos(d *time.Duration) sql.NullInt64 {
+	if d == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*d / time.Minute), Valid: true}
+}
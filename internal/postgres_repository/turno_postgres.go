@@ -6,34 +6,137 @@ import (
 	"time"
 
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/lib/pq"
 )
 
+// lockAgenda es la clave del advisory lock que serializa los cambios de horario
+// que se verifican dentro de la transacción.
+const lockAgenda = 1
+
+// columnasTurno es el orden de columnas que espera scanTurno.
+const columnasTurno = `t.id, t.inicio, t.duracion, t.cliente_id, t.estado,
+	t.cancelado_por, t.motivo_cancelacion, t.cancelacion_tardia, t.cancelado_en, t.sena, t.serie_id`
+
+// TurnoPostgresRepository guarda el inicio de cada turno como timestamptz y lo
+// devuelve separado en fecha y hora de zona. Las fechas de cancelación y del
+// historial también se devuelven en zona.
 type TurnoPostgresRepository struct {
-	db *sql.DB
+	db   *sql.DB
+	zona *time.Location
 }
 
-func NewTurnoPostgresRepository(db *sql.DB) *TurnoPostgresRepository {
-	return &TurnoPostgresRepository{db: db}
+func NewTurnoPostgresRepository(db *sql.DB, zona *time.Location) *TurnoPostgresRepository {
+	return &TurnoPostgresRepository{db: db, zona: zona}
 }
 
-func (r *TurnoPostgresRepository) CreateOrUpdate(ctx context.Context, t *domain.Turno) (*domain.Turno, error) {
+// CreateOrUpdate guarda el turno y reemplaza sus servicios dentro de una misma transacción.
+// El estado solo se escribe al crear; después cambia únicamente a través de CambiarEstado.
+func (r *TurnoPostgresRepository) CreateOrUpdate(ctx context.Context, t *domain.Turno, verificar func(domain.TurnosEnRango) error) (*domain.Turno, error) {
+	if err := r.GuardarVarios(ctx, []*domain.Turno{t}, verificar); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
 
-	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO turno(id, fecha, hora, cliente_id)
-	VALUES ($1, $2, $3, $4)
-	ON CONFLICT(id)
-	DO UPDATE SET fecha = EXCLUDED.fecha,
-	hora = EXCLUDED.hora,
-	cliente_id = EXCLUDED.cliente_id`,
-		t.ID, t.Fecha, t.Hora.String(), t.Cliente.ID)
+// GuardarVarios guarda todos los turnos o ninguno.
+func (r *TurnoPostgresRepository) GuardarVarios(ctx context.Context, turnos []*domain.Turno, verificar func(domain.TurnosEnRango) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.verificarAgenda(ctx, tx, verificar); err != nil {
+		return err
+	}
+	for _, t := range turnos {
+		if err := guardarTurno(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *TurnoPostgresRepository) CrearSerie(ctx context.Context, s *domain.Serie, verificar func(domain.TurnosEnRango) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.verificarAgenda(ctx, tx, verificar); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO serie(id, cliente_id, rrule, fecha_inicio) VALUES ($1, $2, $3, $4)`,
+		s.ID, s.ClienteID, s.Regla.String(), s.Inicio); err != nil {
+		return err
+	}
+	for _, t := range s.Turnos {
+		if err := guardarTurno(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetSerie devuelve la serie con todos sus turnos, ordenados por fecha.
+func (r *TurnoPostgresRepository) GetSerie(ctx context.Context, id string) (*domain.Serie, error) {
+	var s domain.Serie
+	var rrule string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, cliente_id, rrule, fecha_inicio FROM serie WHERE id = $1`, id).
+		Scan(&s.ID, &s.ClienteID, &rrule, &s.Inicio)
+	if err != nil {
+		return nil, err
+	}
+	if s.Regla, err = domain.ParseRRule(rrule); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+columnasTurno+`
+		FROM turno t
+		WHERE t.serie_id = $1
+		ORDER BY t.inicio`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		t, err := r.scanTurno(rows)
+		if err != nil {
+			return nil, err
+		}
+		s.Turnos = append(s.Turnos, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := cargarServicios(ctx, r.db, s.Turnos); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *TurnoPostgresRepository) GetByID(ctx context.Context, id string) (*domain.Turno, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+columnasTurno+` FROM turno t WHERE t.id = $1`, id)
+	t, err := r.scanTurno(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := cargarServicios(ctx, r.db, []*domain.Turno{t}); err != nil {
 		return nil, err
 	}
 	return t, nil
 }
 
-func (r *TurnoPostgresRepository) GetAll(ctx context.Context) ([]*domain.Turno, error) {
-	rows, err := r.db.QueryContext(ctx, `SELECT id, fecha, hora, cliente_id FROM turno`)
+func (r *TurnoPostgresRepository) GetAll(ctx context.Context, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+columnasTurno+`
+		FROM turno t
+		WHERE cardinality($1::text[]) = 0 OR t.estado = ANY($1)`, pq.Array(nombresEstado(estados)))
 	if err != nil {
 		return nil, err
 	}
@@ -41,59 +144,456 @@ func (r *TurnoPostgresRepository) GetAll(ctx context.Context) ([]*domain.Turno,
 
 	var turnos []*domain.Turno
 	for rows.Next() {
-		var t domain.Turno
-		var horaStr string
-		var cliente_id string
-		if err := rows.Scan(&t.ID, &t.Fecha, &horaStr, &cliente_id); err != nil {
+		t, err := r.scanTurno(rows)
+		if err != nil {
 			return nil, err
 		}
-		horaParsed, err := domain.ParseTimeOfDay(horaStr)
+		turnos = append(turnos, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := cargarServicios(ctx, r.db, turnos); err != nil {
+		return nil, err
+	}
+	return turnos, nil
+}
+
+// Buscar compara las fechas del filtro contra los días del negocio: hasta
+// incluye los turnos que empiezan antes de la medianoche local de ese día.
+func (r *TurnoPostgresRepository) Buscar(ctx context.Context, f domain.FiltroTurnos) ([]*domain.Turno, error) {
+	var desde, hasta sql.NullTime
+	if !f.Desde.IsZero() {
+		desde = sql.NullTime{Time: domain.InicioDelDia(f.Desde, r.zona), Valid: true}
+	}
+	if !f.Hasta.IsZero() {
+		hasta = sql.NullTime{Time: domain.InicioDelDia(f.Hasta.AddDate(0, 0, 1), r.zona), Valid: true}
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+columnasTurno+`
+		FROM turno t
+		WHERE ($1::timestamptz IS NULL OR t.inicio >= $1)
+		AND ($2::timestamptz IS NULL OR t.inicio < $2)
+		AND ($3 = '' OR t.cliente_id = $3)
+		AND (cardinality($4::text[]) = 0 OR t.estado = ANY($4))
+		ORDER BY t.inicio`, desde, hasta, f.ClienteID, pq.Array(nombresEstado(f.Estados)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var turnos []*domain.Turno
+	for rows.Next() {
+		t, err := r.scanTurno(rows)
 		if err != nil {
 			return nil, err
 		}
-		t.Hora = horaParsed
-		t.Cliente = domain.Cliente{ID: cliente_id}
-		turnos = append(turnos, &t)
+		turnos = append(turnos, t)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if err := cargarServicios(ctx, r.db, turnos); err != nil {
+		return nil, err
+	}
 	return turnos, nil
 }
 
-func (r *TurnoPostgresRepository) GetByFecha(ctx context.Context, fecha time.Time) ([]*domain.Turno, error) {
+func (r *TurnoPostgresRepository) GetByFecha(ctx context.Context, fecha time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
+	return r.GetEntreFechas(ctx, fecha, fecha, estados...)
+}
+
+// GetEntreFechas trae en la misma consulta el nombre, el teléfono y la
+// preferencia horaria del cliente de cada turno.
+func (r *TurnoPostgresRepository) GetEntreFechas(ctx context.Context, desde, hasta time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT 
-		t.id, t.fecha, t.hora, t.cliente_id, 
-		c.id, c.nombre, c.telefono, c.preferenciahoraria 
-		FROM turno t 
-		INNER JOIN cliente c ON t.cliente_id = c.id 
-		WHERE t.fecha = $1`, fecha)
+		`SELECT `+columnasTurno+`,
+		c.nombre, c.telefono, c.preferenciahoraria
+		FROM turno t
+		INNER JOIN cliente c ON t.cliente_id = c.id
+		WHERE t.inicio >= $1 AND t.inicio < $2
+		AND (cardinality($3::text[]) = 0 OR t.estado = ANY($3))
+		ORDER BY t.inicio`, domain.InicioDelDia(desde, r.zona), domain.InicioDelDia(hasta.AddDate(0, 0, 1), r.zona),
+		pq.Array(nombresEstado(estados)))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var turnos []*domain.Turno
-	var horaStr string
-	var cliente_id string // cliente_id sirve para descartar el dato redundante
 	for rows.Next() {
-		var t domain.Turno
-		if err := rows.Scan(&t.ID, &t.Fecha, &horaStr, &cliente_id, &t.Cliente.ID, &t.Cliente.Nombre, &t.Cliente.Telefono, &t.Cliente.PreferenciaHoraria); err != nil {
+		var c domain.Cliente
+		t, err := r.scanTurno(rows, &c.Nombre, &c.Telefono, &c.PreferenciaHoraria)
+		if err != nil {
 			return nil, err
 		}
-		t.Hora, err = domain.ParseTimeOfDay(horaStr)
+		c.ID = t.Cliente.ID
+		t.Cliente = c
+		turnos = append(turnos, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := cargarServicios(ctx, r.db, turnos); err != nil {
+		return nil, err
+	}
+	return turnos, nil
+}
+
+// GetEnRango compara el inicio y el fin de cada turno contra la ventana pedida;
+// así también aparecen los turnos que empiezan antes y terminan dentro de ella.
+func (r *TurnoPostgresRepository) GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Turno, error) {
+	return r.turnosEnRango(ctx, r.db, desde, hasta)
+}
+
+// Reprogramar mueve t a su nueva fecha y hora y registra e en el historial.
+func (r *TurnoPostgresRepository) Reprogramar(ctx context.Context, t *domain.Turno, e domain.EventoTurno, verificar func(domain.TurnosEnRango) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.verificarAgenda(ctx, tx, verificar); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE turno SET inicio = $2 WHERE id = $1`, t.ID, t.Inicio())
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	if err := insertarEvento(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// verificarAgenda toma el lock de la agenda hasta que termine tx y le pasa a
+// verificar los turnos que se leen dentro de tx. Como el lock se toma antes de
+// leer, cada consulta ve lo que guardaron las transacciones que lo tuvieron
+// antes. Sin verificar no hace nada.
+func (r *TurnoPostgresRepository) verificarAgenda(ctx context.Context, tx *sql.Tx, verificar func(domain.TurnosEnRango) error) error {
+	if verificar == nil {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, lockAgenda); err != nil {
+		return err
+	}
+	return verificar(func(desde, hasta time.Time) ([]*domain.Turno, error) {
+		return r.turnosEnRango(ctx, tx, desde, hasta)
+	})
+}
+
+func (r *TurnoPostgresRepository) turnosEnRango(ctx context.Context, db querier, desde, hasta time.Time) ([]*domain.Turno, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT `+columnasTurno+`
+		FROM turno t
+		WHERE t.estado <> 'cancelado'
+		AND t.inicio < $2
+		AND t.inicio + t.duracion * INTERVAL '1 minute' > $1`, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var turnos []*domain.Turno
+	for rows.Next() {
+		t, err := r.scanTurno(rows)
 		if err != nil {
 			return nil, err
 		}
-		turnos = append(turnos, &t)
+		turnos = append(turnos, t)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if err := cargarServicios(ctx, db, turnos); err != nil {
+		return nil, err
+	}
 	return turnos, nil
 }
 
+func (r *TurnoPostgresRepository) CambiarEstado(ctx context.Context, t *domain.Turno, e domain.EventoTurno) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := guardarEstado(ctx, tx, t, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *TurnoPostgresRepository) CambiarEstados(ctx context.Context, turnos []*domain.Turno, eventos []domain.EventoTurno) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, t := range turnos {
+		if err := guardarEstado(ctx, tx, t, eventos[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CerrarAgenda guarda un cierre entero en una transacción, con la agenda
+// bloqueada desde antes de verificar hasta el final.
+func (r *TurnoPostgresRepository) CerrarAgenda(ctx context.Context, informe *domain.InformeCierre, eventos []domain.EventoTurno, verificar func(domain.TurnosEnRango) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.verificarAgenda(ctx, tx, verificar); err != nil {
+		return err
+	}
+	for _, b := range informe.Bloqueos {
+		if err := guardarBloqueo(ctx, tx, b); err != nil {
+			return err
+		}
+	}
+	for i, a := range informe.Afectados {
+		if err := guardarEstado(ctx, tx, a.Turno, eventos[i]); err != nil {
+			return err
+		}
+		if a.Notificacion != nil {
+			if err := guardarNotificacion(ctx, tx, a.Notificacion); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// guardarEstado escribe el estado y la cancelación de t y agrega e a su historial.
+func guardarEstado(ctx context.Context, tx *sql.Tx, t *domain.Turno, e domain.EventoTurno) error {
+	var origen, motivo sql.NullString
+	var tardia bool
+	var canceladoEn sql.NullTime
+	if c := t.Cancelacion; c != nil {
+		origen = sql.NullString{String: c.Origen.String(), Valid: true}
+		motivo = sql.NullString{String: c.Motivo, Valid: true}
+		tardia = c.Tardia
+		canceladoEn = sql.NullTime{Time: c.Fecha, Valid: true}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE turno SET estado = $2,
+		cancelado_por = $3,
+		motivo_cancelacion = $4,
+		cancelacion_tardia = $5,
+		cancelado_en = $6
+		WHERE id = $1`,
+		t.ID, t.Estado.String(), origen, motivo, tardia, canceladoEn); err != nil {
+		return err
+	}
+	return insertarEvento(ctx, tx, e)
+}
+
+func (r *TurnoPostgresRepository) GetHistorial(ctx context.Context, turnoID string) ([]domain.EventoTurno, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT turno_id, estado_anterior, estado, fecha, detalle
+		FROM turno_historial
+		WHERE turno_id = $1
+		ORDER BY fecha, id`, turnoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var eventos []domain.EventoTurno
+	for rows.Next() {
+		e, err := r.scanEvento(rows)
+		if err != nil {
+			return nil, err
+		}
+		eventos = append(eventos, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return eventos, nil
+}
+
+// scanEvento lee una fila del historial con la fecha en la zona del negocio.
+func (r *TurnoPostgresRepository) scanEvento(s scanner) (domain.EventoTurno, error) {
+	var e domain.EventoTurno
+	var anteriorStr, estadoStr string
+	if err := s.Scan(&e.TurnoID, &anteriorStr, &estadoStr, &e.Fecha, &e.Detalle); err != nil {
+		return domain.EventoTurno{}, err
+	}
+	var err error
+	if e.EstadoAnterior, err = domain.ParseEstadoTurno(anteriorStr); err != nil {
+		return domain.EventoTurno{}, err
+	}
+	if e.Estado, err = domain.ParseEstadoTurno(estadoStr); err != nil {
+		return domain.EventoTurno{}, err
+	}
+	e.Fecha = e.Fecha.In(r.zona)
+	return e, nil
+}
+
+// ContarIncumplimientos toma el inicio del turno para las ausencias y la fecha
+// de la cancelación para las tardías.
+func (r *TurnoPostgresRepository) ContarIncumplimientos(ctx context.Context, clienteID string, desde time.Time) (int, int, error) {
+	var ausencias, tardias int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT
+		count(*) FILTER (WHERE t.estado = 'ausente' AND t.inicio >= $2),
+		count(*) FILTER (WHERE t.cancelacion_tardia AND t.cancelado_en >= $2)
+		FROM turno t
+		WHERE t.cliente_id = $1`, clienteID, desde).Scan(&ausencias, &tardias)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ausencias, tardias, nil
+}
+
 func (r *TurnoPostgresRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.ExecContext(ctx, `DELETE FROM turno WHERE id = $1`, id)
 	return err
 }
+
+// cargarServicios completa los servicios de cada turno con una sola consulta.
+// El precio, la duración, los márgenes y las fases que se devuelven son los que
+// quedaron registrados al reservar.
+func cargarServicios(ctx context.Context, db querier, turnos []*domain.Turno) error {
+	if len(turnos) == 0 {
+		return nil
+	}
+	porID := make(map[string]*domain.Turno, len(turnos))
+	ids := make([]string, 0, len(turnos))
+	for _, t := range turnos {
+		porID[t.ID] = t
+		ids = append(ids, t.ID)
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT s.id, s.nombre, ts.duracion, ts.precio, s.activo, ts.margen_antes, ts.margen_despues, ts.fases, ts.turno_id
+		FROM turno_servicio ts
+		INNER JOIN servicio s ON ts.servicio_id = s.id
+		WHERE ts.turno_id = ANY($1)
+		ORDER BY ts.orden`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var turnoID string
+		s, err := scanServicio(rows, &turnoID)
+		if err != nil {
+			return err
+		}
+		porID[turnoID].Servicios = append(porID[turnoID].Servicios, *s)
+	}
+	return rows.Err()
+}
+
+// querier lo cumplen tanto *sql.DB como *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// scanner lo cumplen tanto *sql.Row como *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTurno lee las columnas de columnasTurno y, a continuación, las que se
+// pidan en extra. Del cliente solo queda cargado el ID.
+func (r *TurnoPostgresRepository) scanTurno(s scanner, extra ...any) (*domain.Turno, error) {
+	var t domain.Turno
+	var inicio time.Time
+	var estadoStr string
+	var duracion int
+	var cliente_id string
+	var origen, motivo sql.NullString
+	var tardia bool
+	var canceladoEn sql.NullTime
+	var serieID sql.NullString
+	dest := append([]any{&t.ID, &inicio, &duracion, &cliente_id, &estadoStr,
+		&origen, &motivo, &tardia, &canceladoEn, &t.Sena, &serieID}, extra...)
+	if err := s.Scan(dest...); err != nil {
+		return nil, err
+	}
+	estado, err := domain.ParseEstadoTurno(estadoStr)
+	if err != nil {
+		return nil, err
+	}
+	t.Fecha, t.Hora = domain.Separar(inicio, r.zona)
+	t.Zona = r.zona
+	t.Estado = estado
+	t.Duracion = time.Duration(duracion) * time.Minute
+	t.Cliente = domain.Cliente{ID: cliente_id}
+	t.SerieID = serieID.String
+	if origen.Valid {
+		o, err := domain.ParseOrigenCancelacion(origen.String)
+		if err != nil {
+			return nil, err
+		}
+		t.Cancelacion = &domain.Cancelacion{
+			Origen: o,
+			Motivo: motivo.String,
+			Tardia: tardia,
+			Fecha:  canceladoEn.Time.In(r.zona),
+		}
+	}
+	return &t, nil
+}
+
+// guardarTurno inserta o actualiza t y reemplaza sus servicios. El estado solo se
+// escribe al crear.
+func guardarTurno(ctx context.Context, tx *sql.Tx, t *domain.Turno) error {
+	serieID := sql.NullString{String: t.SerieID, Valid: t.SerieID != ""}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO turno(id, inicio, duracion, cliente_id, estado, sena, serie_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT(id)
+	DO UPDATE SET inicio = EXCLUDED.inicio,
+	duracion = EXCLUDED.duracion,
+	cliente_id = EXCLUDED.cliente_id,
+	sena = EXCLUDED.sena,
+	serie_id = EXCLUDED.serie_id`,
+		t.ID, t.Inicio(), int(t.Duracion/time.Minute), t.Cliente.ID, t.Estado.String(), t.Sena, serieID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM turno_servicio WHERE turno_id = $1`, t.ID); err != nil {
+		return err
+	}
+	for i, s := range t.Servicios {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO turno_servicio(turno_id, servicio_id, orden, precio, duracion, margen_antes, margen_despues, fases)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			t.ID, s.ID, i, s.Precio, int(s.Duracion/time.Minute),
+			minutosNulos(s.MargenAntes), minutosNulos(s.MargenDespues), pq.Array(fasesEnMinutos(s.Fases))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertarEvento(ctx context.Context, tx *sql.Tx, e domain.EventoTurno) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO turno_historial(turno_id, estado_anterior, estado, fecha, detalle)
+		VALUES ($1, $2, $3, $4, $5)`,
+		e.TurnoID, e.EstadoAnterior.String(), e.Estado.String(), e.Fecha, e.Detalle)
+	return err
+}
+
+func nombresEstado(estados []domain.EstadoTurno) []string {
+	nombres := make([]string, 0, len(estados))
+	for _, e := range estados {
+		nombres = append(nombres, e.String())
+	}
+	return nombres
+}
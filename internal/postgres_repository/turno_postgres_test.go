@@ -0,0 +1,52 @@
+package postgresrepository
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fila hace de una fila ya leída de la base: Scan copia cada valor en su destino.
+type fila []any
+
+func (f fila) Scan(dest ...any) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(f[i]))
+	}
+	return nil
+}
+
+func TestTurnoPostgresRepository_Zona(t *testing.T) {
+	zona, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	require.NoError(t, err)
+	r := NewTurnoPostgresRepository(nil, zona)
+	// el driver devuelve los timestamptz en UTC
+	instante := time.Date(2025, 6, 2, 1, 30, 0, 0, time.UTC)
+
+	t.Run("La cancelación se devuelve en la zona del negocio", func(t *testing.T) {
+		turno, err := r.scanTurno(fila{"t1", instante, 30, "c1", "cancelado",
+			sql.NullString{String: "negocio", Valid: true}, sql.NullString{String: "cierre", Valid: true},
+			false, sql.NullTime{Time: instante, Valid: true}, 0.0, sql.NullString{}})
+		require.NoError(t, err)
+
+		require.NotNil(t, turno.Cancelacion)
+		assert.Equal(t, zona, turno.Cancelacion.Fecha.Location())
+		assert.True(t, instante.Equal(turno.Cancelacion.Fecha))
+		assert.Equal(t, "2025-06-01T22:30:00-03:00", turno.Cancelacion.Fecha.Format(time.RFC3339))
+	})
+
+	t.Run("El historial se devuelve en la zona del negocio", func(t *testing.T) {
+		e, err := r.scanEvento(fila{"t1", "pendiente", "confirmado", instante, ""})
+		require.NoError(t, err)
+
+		assert.Equal(t, zona, e.Fecha.Location())
+		assert.True(t, instante.Equal(e.Fecha))
+		assert.Equal(t, "2025-06-01T22:30:00-03:00", e.Fecha.Format(time.RFC3339))
+		assert.Equal(t, domain.Confirmado, e.Estado)
+	})
+}
@@ -9,16 +9,120 @@ import (
 
 type ClienteRepository interface {
 	CreateOrUpdate(ctx context.Context, c *domain.Cliente) (*domain.Cliente, error)
+	// Delete devuelve ErrClienteConTurnos si el cliente tiene turnos.
 	Delete(ctx context.Context, id string) error
 	GetByID(ctx context.Context, id string) (*domain.Cliente, error)
 	GetAll(ctx context.Context) ([]*domain.Cliente, error)
+	// GuardarRestriccion actualiza solo la restricción del cliente y la fecha en que se levantó.
+	GuardarRestriccion(ctx context.Context, c *domain.Cliente) error
+	// BuscarPorTelefono devuelve el cliente con ese teléfono, o nil si no hay ninguno.
+	BuscarPorTelefono(ctx context.Context, telefono string) (*domain.Cliente, error)
+	// Search busca clientes por parte del nombre, sin distinguir acentos ni
+	// mayúsculas, o por dígitos de su teléfono. Devuelve los más parecidos primero.
+	Search(ctx context.Context, consulta string, limite int) ([]*domain.Cliente, error)
+	// NombresSimilares devuelve los pares de clientes cuyos nombres, sin acentos
+	// ni mayúsculas, tienen al menos esa similitud.
+	NombresSimilares(ctx context.Context, umbral float64) ([]domain.NombresSimilares, error)
+	// Fusionar pasa turnos, series, lista de espera, notificaciones y ficha
+	// técnica del duplicado al sobreviviente, guarda la restricción del
+	// sobreviviente, borra el duplicado y deja la entrada de auditoría, todo en
+	// una transacción.
+	Fusionar(ctx context.Context, f *domain.Fusion) error
+	// Auditoria devuelve las entradas de auditoría del cliente, de la más reciente a la más vieja.
+	Auditoria(ctx context.Context, clienteID string) ([]*domain.EntradaAuditoria, error)
+	RegistrarAuditoria(ctx context.Context, e *domain.EntradaAuditoria) error
+	// Exportar devuelve todas las filas que se guardan del cliente.
+	Exportar(ctx context.Context, id string) (*domain.ExportacionCliente, error)
+	// Anonimizar borra los datos personales del cliente y conserva sus turnos,
+	// y deja la entrada de auditoría en la misma transacción.
+	Anonimizar(ctx context.Context, a *domain.Anonimizacion) error
+	// TurnosPorVenir devuelve los IDs de los turnos pendientes o confirmados del
+	// cliente que empiezan después de desde.
+	TurnosPorVenir(ctx context.Context, clienteID string, desde time.Time) ([]string, error)
 }
 
+// Los métodos de TurnoRepository que reciben verificar toman el lock de la
+// agenda y le pasan, dentro de la transacción que guarda, una forma de leer los
+// turnos ya guardados. Si verificar devuelve un error no se guarda nada. Así dos
+// reservas simultáneas no pueden tomar el mismo lugar: la segunda espera a que
+// termine la primera y ve lo que guardó.
 type TurnoRepository interface {
-	CreateOrUpdate(ctx context.Context, t *domain.Turno) (*domain.Turno, error)
+	CreateOrUpdate(ctx context.Context, t *domain.Turno, verificar func(domain.TurnosEnRango) error) (*domain.Turno, error)
 	Delete(ctx context.Context, id string) error
-	GetByFecha(ctx context.Context, fecha time.Time) ([]*domain.Turno, error)
-	GetAll(ctx context.Context) ([]*domain.Turno, error)
+	GetByID(ctx context.Context, id string) (*domain.Turno, error)
+	// GetByFecha y GetAll devuelven solo los turnos en alguno de los estados
+	// indicados; sin estados no filtran.
+	GetByFecha(ctx context.Context, fecha time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error)
+	// GetEntreFechas devuelve los turnos de desde a hasta (inclusive) con los datos
+	// de contacto del cliente cargados.
+	GetEntreFechas(ctx context.Context, desde, hasta time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error)
+	GetAll(ctx context.Context, estados ...domain.EstadoTurno) ([]*domain.Turno, error)
+	// Buscar devuelve los turnos que cumplen f, ordenados por fecha y hora.
+	Buscar(ctx context.Context, f domain.FiltroTurnos) ([]*domain.Turno, error)
+	// GetEnRango devuelve los turnos no cancelados que ocupan al menos un minuto de [desde, hasta).
+	GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Turno, error)
+	// CambiarEstado guarda el estado actual de t y agrega el evento a su historial en una transacción.
+	CambiarEstado(ctx context.Context, t *domain.Turno, e domain.EventoTurno) error
+	// CambiarEstados hace lo mismo con varios turnos en una sola transacción;
+	// eventos va en el mismo orden que turnos.
+	CambiarEstados(ctx context.Context, turnos []*domain.Turno, eventos []domain.EventoTurno) error
+	GetHistorial(ctx context.Context, turnoID string) ([]domain.EventoTurno, error)
+	// Reprogramar guarda la nueva fecha y hora de t y el evento en una transacción.
+	Reprogramar(ctx context.Context, t *domain.Turno, e domain.EventoTurno, verificar func(domain.TurnosEnRango) error) error
+	// GuardarVarios guarda varios turnos en una sola transacción.
+	GuardarVarios(ctx context.Context, turnos []*domain.Turno, verificar func(domain.TurnosEnRango) error) error
+	// CrearSerie guarda la serie y todos sus turnos en una sola transacción.
+	CrearSerie(ctx context.Context, s *domain.Serie, verificar func(domain.TurnosEnRango) error) error
+	GetSerie(ctx context.Context, id string) (*domain.Serie, error)
+	// CerrarAgenda guarda en una sola transacción los bloqueos del informe, la
+	// cancelación de cada afectado con su evento (eventos va en el mismo orden que
+	// informe.Afectados) y las notificaciones que no sean nil. verificar corre con
+	// la agenda bloqueada, antes de escribir nada.
+	CerrarAgenda(ctx context.Context, informe *domain.InformeCierre, eventos []domain.EventoTurno, verificar func(domain.TurnosEnRango) error) error
+	// ContarIncumplimientos cuenta las ausencias y cancelaciones tardías del cliente desde la fecha dada.
+	ContarIncumplimientos(ctx context.Context, clienteID string, desde time.Time) (ausencias, tardias int, err error)
+}
+
+type BloqueoRepository interface {
+	CreateOrUpdate(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error)
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (*domain.Bloqueo, error)
+	GetAll(ctx context.Context) ([]*domain.Bloqueo, error)
+	// GetEnRango devuelve los bloqueos que pueden aplicar a algún día entre desde y
+	// hasta, inclusive; los semanales vienen una sola vez.
+	GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Bloqueo, error)
+}
+
+type ListaEsperaRepository interface {
+	CreateOrUpdate(ctx context.Context, e *domain.EntradaEspera) (*domain.EntradaEspera, error)
+	// GetByID y GetActivas cargan también la oferta pendiente de cada entrada.
+	GetByID(ctx context.Context, id string) (*domain.EntradaEspera, error)
+	// GetActivas devuelve las entradas esperando o con oferta, de la más vieja a la más nueva.
+	GetActivas(ctx context.Context) ([]*domain.EntradaEspera, error)
+	// GetEsperando devuelve, por orden de llegada, las entradas sin oferta cuyo rango incluye fecha.
+	GetEsperando(ctx context.Context, fecha time.Time) ([]*domain.EntradaEspera, error)
+	// GuardarOferta guarda la oferta y el estado de su entrada en una sola transacción.
+	GuardarOferta(ctx context.Context, o *domain.Oferta, e *domain.EntradaEspera) error
+	GetOferta(ctx context.Context, id string) (*domain.Oferta, error)
+	// GetOfertasVencidas devuelve las ofertas pendientes cuyo plazo terminó antes de ahora.
+	GetOfertasVencidas(ctx context.Context, ahora time.Time) ([]*domain.Oferta, error)
+	// GetOfertasPendientes devuelve las ofertas pendientes que siguen vigentes a
+	// ahora y cuyo lugar cae entre los días desde y hasta, ambos inclusive.
+	GetOfertasPendientes(ctx context.Context, desde, hasta, ahora time.Time) ([]*domain.Oferta, error)
+}
+
+type ServicioRepository interface {
+	CreateOrUpdate(ctx context.Context, s *domain.Servicio) (*domain.Servicio, error)
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (*domain.Servicio, error)
+	GetAll(ctx context.Context) ([]*domain.Servicio, error)
+}
+
+type HorarioLaboralRepository interface {
+	// CreateOrUpdate reemplaza todas las franjas del día de h.
+	CreateOrUpdate(ctx context.Context, h *domain.HorarioLaboral) (*domain.HorarioLaboral, error)
+	GetByDia(ctx context.Context, dia time.Weekday) (*domain.HorarioLaboral, error)
+	GetAll(ctx context.Context) ([]*domain.HorarioLaboral, error)
 }
 
 /*
@@ -35,3 +139,21 @@ Con contexto: se puede cortar a mitad, ahorrando recursos.
 
 Siempre se propaga desde el handler hacia abajo.
 */
+
+type NotificacionRepository interface {
+	// Create deja la notificación pendiente de envío.
+	Create(ctx context.Context, n *domain.Notificacion) error
+}
+
+type FichaRepository interface {
+	// CreateOrUpdate devuelve sql.ErrNoRows si el cliente no existe o la
+	// entrada es de otro cliente.
+	CreateOrUpdate(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error)
+	Delete(ctx context.Context, clienteID, id string) error
+	GetByID(ctx context.Context, clienteID, id string) (*domain.EntradaFicha, error)
+	// GetByCliente devuelve la ficha del cliente, de la entrada más reciente a la más vieja.
+	GetByCliente(ctx context.Context, clienteID string) ([]*domain.EntradaFicha, error)
+	// UltimasFormulas devuelve, por ID de cliente, la entrada más reciente con
+	// fórmula de cada uno de esos clientes que tenga alguna.
+	UltimasFormulas(ctx context.Context, clienteIDs []string) (map[string]*domain.EntradaFicha, error)
+}
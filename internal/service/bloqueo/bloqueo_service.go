@@ -0,0 +1,83 @@
+package bloqueo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
+	"github.com/google/uuid"
+)
+
+type BloqueoService interface {
+	Create(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error)
+	Update(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error)
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (*domain.Bloqueo, error)
+	GetAll(ctx context.Context) ([]*domain.Bloqueo, error)
+	GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Bloqueo, error)
+	// Bloqueante devuelve el primer bloqueo con el que choca t, o nil si no choca con ninguno.
+	Bloqueante(ctx context.Context, t *domain.Turno) (*domain.Bloqueo, error)
+}
+
+type bloqueoService struct {
+	repo repository.BloqueoRepository
+}
+
+func NewBloqueoService(repo repository.BloqueoRepository) *bloqueoService {
+	return &bloqueoService{repo: repo}
+}
+
+func (s bloqueoService) Create(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	return s.repo.CreateOrUpdate(ctx, b)
+}
+
+func (s bloqueoService) Update(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error) {
+	if b.ID == "" {
+		return nil, errors.New("ID requerido para actualizar")
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrUpdate(ctx, b)
+}
+
+func (s bloqueoService) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s bloqueoService) GetByID(ctx context.Context, id string) (*domain.Bloqueo, error) {
+	if id == "" {
+		return nil, errors.New("ID requerido para obtener bloqueo")
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s bloqueoService) GetAll(ctx context.Context) ([]*domain.Bloqueo, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s bloqueoService) GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Bloqueo, error) {
+	return s.repo.GetEnRango(ctx, desde, hasta)
+}
+
+func (s bloqueoService) Bloqueante(ctx context.Context, t *domain.Turno) (*domain.Bloqueo, error) {
+	// un turno que pasa la medianoche puede chocar con un bloqueo del día siguiente
+	bloqueos, err := s.repo.GetEnRango(ctx, t.Fecha, t.Fecha.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bloqueos {
+		if b.SeSolapaCon(t) {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,135 @@
+package bloqueo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/bloqueo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBloqueoRepository struct {
+	mock.Mock
+}
+
+func (m *MockBloqueoRepository) CreateOrUpdate(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error) {
+	args := m.Called(ctx, b)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Bloqueo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockBloqueoRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBloqueoRepository) GetByID(ctx context.Context, id string) (*domain.Bloqueo, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Bloqueo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockBloqueoRepository) GetAll(ctx context.Context) ([]*domain.Bloqueo, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Bloqueo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockBloqueoRepository) GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Bloqueo, error) {
+	args := m.Called(ctx, desde, hasta)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Bloqueo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestBloqueoService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		bloqueo *domain.Bloqueo
+		wantErr string
+	}{
+		{"Sin motivo", makeBloqueo("", "02", 13, 14), "motivo requerido"},
+		{"Termina antes de empezar", makeBloqueo("almuerzo", "02", 14, 13), "el bloqueo debe terminar después de empezar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := setupBloqueoServiceWithMock(t)
+			res, err := s.Create(context.Background(), tt.bloqueo)
+			assert.Nil(t, res)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+	t.Run("Fin de repetición solo en semanales", func(t *testing.T) {
+		s, _ := setupBloqueoServiceWithMock(t)
+		b := makeBloqueo("almuerzo", "02", 13, 14)
+		b.FinRepeticion = b.Fecha.AddDate(0, 1, 0)
+		_, err := s.Create(context.Background(), b)
+		assert.EqualError(t, err, "fin de repetición inválido")
+	})
+	t.Run("Asigna UUID si ID esta vacío", func(t *testing.T) {
+		s, mockRepo := setupBloqueoServiceWithMock(t)
+		nuevo := makeBloqueo("almuerzo", "02", 13, 14)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+		res, err := s.Create(context.Background(), nuevo)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.ID)
+	})
+}
+
+func TestBloqueoService_Bloqueante(t *testing.T) {
+	lunes, _ := time.Parse("2006/01/02", "2025/06/02")
+	almuerzo := makeBloqueo("almuerzo", "02", 13, 14)
+	almuerzo.Semanal = true
+	almuerzo.FinRepeticion = lunes.AddDate(0, 0, 14)
+
+	tests := []struct {
+		name  string
+		fecha time.Time
+		hora  domain.TimeOfDay
+		want  bool
+	}{
+		{"Mismo día dentro del bloqueo", lunes, domain.TimeOfDay{Hour: 13, Minute: 30}, true},
+		{"Mismo día antes del bloqueo", lunes, domain.TimeOfDay{Hour: 11, Minute: 0}, false},
+		{"Termina justo cuando empieza el bloqueo", lunes, domain.TimeOfDay{Hour: 12, Minute: 30}, false},
+		{"Lunes siguiente", lunes.AddDate(0, 0, 7), domain.TimeOfDay{Hour: 13, Minute: 30}, true},
+		{"Último lunes de la repetición", lunes.AddDate(0, 0, 14), domain.TimeOfDay{Hour: 13, Minute: 30}, true},
+		{"Después del fin de la repetición", lunes.AddDate(0, 0, 21), domain.TimeOfDay{Hour: 13, Minute: 30}, false},
+		{"Otro día de la semana", lunes.AddDate(0, 0, 1), domain.TimeOfDay{Hour: 13, Minute: 30}, false},
+		{"Antes de que empiece la repetición", lunes.AddDate(0, 0, -7), domain.TimeOfDay{Hour: 13, Minute: 30}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setupBloqueoServiceWithMock(t)
+			turno := &domain.Turno{Fecha: tt.fecha, Hora: tt.hora, Duracion: 30 * time.Minute}
+			mockRepo.On("GetEnRango", mock.Anything, tt.fecha, tt.fecha.AddDate(0, 0, 1)).Return([]*domain.Bloqueo{almuerzo}, nil)
+
+			got, err := s.Bloqueante(context.Background(), turno)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got != nil)
+		})
+	}
+}
+
+// funciones auxiliares
+func makeBloqueo(motivo, dia string, desde, hasta int) *domain.Bloqueo {
+	fecha, _ := time.Parse("2006/01/02", "2025/06/"+dia)
+	return domain.NewBloqueo("", fecha,
+		domain.TimeOfDay{Hour: desde, Minute: 0}, domain.TimeOfDay{Hour: hasta, Minute: 0},
+		motivo, false, time.Time{})
+}
+
+func setupBloqueoServiceWithMock(t *testing.T) (bloqueo.BloqueoService, *MockBloqueoRepository) {
+	mockRepo := new(MockBloqueoRepository)
+	s := bloqueo.NewBloqueoService(mockRepo)
+	return s, mockRepo
+}
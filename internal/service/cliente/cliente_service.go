@@ -1,8 +1,14 @@
 package cliente
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
@@ -15,14 +21,31 @@ type ClienteService interface {
 	Delete(ctx context.Context, id string) error
 	GetByID(ctx context.Context, id string) (*domain.Cliente, error)
 	GetAll(ctx context.Context) ([]*domain.Cliente, error)
+	Search(ctx context.Context, consulta string, limite int) ([]*domain.Cliente, error)
+	Duplicados(ctx context.Context) ([]*domain.PosibleDuplicado, error)
+	Fusionar(ctx context.Context, sobrevivienteID, duplicadoID string) (*domain.Fusion, error)
+	Auditoria(ctx context.Context, id string) ([]*domain.EntradaAuditoria, error)
+	Exportar(ctx context.Context, id string) (*domain.ExportacionCliente, error)
+	Anonimizar(ctx context.Context, id string) (*domain.Cliente, error)
+	Restringir(ctx context.Context, id string, r domain.RestriccionCliente) (*domain.Cliente, error)
+	LevantarRestriccion(ctx context.Context, id string) (*domain.Cliente, error)
 }
 
+// MaxResultadosBusqueda es cuántos clientes devuelve como mucho una búsqueda.
+const MaxResultadosBusqueda = 20
+
+// UmbralSimilitud es la similitud de nombres (de 0 a 1) a partir de la cual
+// dos clientes se proponen como posibles duplicados.
+const UmbralSimilitud = 0.5
+
 type clienteService struct {
 	repo repository.ClienteRepository
+	// codigoArea completa los teléfonos que se cargan sin código de área.
+	codigoArea string
 }
 
-func NewClienteService(repo repository.ClienteRepository) *clienteService {
-	return &clienteService{repo: repo}
+func NewClienteService(repo repository.ClienteRepository, codigoArea string) *clienteService {
+	return &clienteService{repo: repo, codigoArea: codigoArea}
 }
 
 func (s clienteService) Create(ctx context.Context, c *domain.Cliente) (*domain.Cliente, error) {
@@ -32,9 +55,10 @@ func (s clienteService) Create(ctx context.Context, c *domain.Cliente) (*domain.
 	if c.ID == "" {
 		c.ID = uuid.New().String()
 	}
-
-	return s.repo.CreateOrUpdate(ctx, c)
-
+	if err := s.verificarTelefono(ctx, c); err != nil {
+		return nil, err
+	}
+	return s.guardar(ctx, c)
 }
 
 func (s clienteService) Update(ctx context.Context, c *domain.Cliente) (*domain.Cliente, error) {
@@ -44,7 +68,45 @@ func (s clienteService) Update(ctx context.Context, c *domain.Cliente) (*domain.
 	if err := c.Validate(); err != nil {
 		return nil, err
 	}
-	return s.repo.CreateOrUpdate(ctx, c)
+	// a un cliente anonimizado no se le vuelven a cargar datos personales
+	actual, err := s.repo.GetByID(ctx, c.ID)
+	if err == nil && !actual.Anonimizado.IsZero() {
+		return nil, domain.ErrClienteAnonimizado
+	}
+	if err := s.verificarTelefono(ctx, c); err != nil {
+		return nil, err
+	}
+	return s.guardar(ctx, c)
+}
+
+// verificarTelefono normaliza el teléfono de c y controla que no lo tenga otro
+// cliente.
+func (s clienteService) verificarTelefono(ctx context.Context, c *domain.Cliente) error {
+	telefono, err := domain.NormalizarTelefono(c.Telefono, s.codigoArea)
+	if err != nil {
+		return err
+	}
+	c.Telefono = telefono
+	otro, err := s.repo.BuscarPorTelefono(ctx, telefono)
+	if err != nil {
+		return err
+	}
+	if otro != nil && otro.ID != c.ID {
+		return &domain.TelefonoDuplicadoError{Cliente: otro}
+	}
+	return nil
+}
+
+// guardar guarda c. Si otro cliente se quedó con el mismo teléfono después de
+// verificarlo, la restricción de la base lo frena y se avisa quién es.
+func (s clienteService) guardar(ctx context.Context, c *domain.Cliente) (*domain.Cliente, error) {
+	res, err := s.repo.CreateOrUpdate(ctx, c)
+	if errors.Is(err, domain.ErrTelefonoDuplicado) {
+		if otro, _ := s.repo.BuscarPorTelefono(ctx, c.Telefono); otro != nil {
+			return nil, &domain.TelefonoDuplicadoError{Cliente: otro}
+		}
+	}
+	return res, err
 }
 
 func (s clienteService) Delete(ctx context.Context, id string) error {
@@ -61,3 +123,201 @@ func (s clienteService) GetByID(ctx context.Context, id string) (*domain.Cliente
 func (s clienteService) GetAll(ctx context.Context) ([]*domain.Cliente, error) {
 	return s.repo.GetAll(ctx)
 }
+
+// Search busca clientes por nombre o teléfono para encontrarlos rápido en el
+// mostrador. Un limite fuera de rango se lleva a MaxResultadosBusqueda.
+func (s clienteService) Search(ctx context.Context, consulta string, limite int) ([]*domain.Cliente, error) {
+	consulta = strings.TrimSpace(consulta)
+	if utf8.RuneCountInString(consulta) < 2 {
+		return nil, domain.ErrBusquedaCorta
+	}
+	if limite <= 0 || limite > MaxResultadosBusqueda {
+		limite = MaxResultadosBusqueda
+	}
+	return s.repo.Search(ctx, consulta, limite)
+}
+
+// Restringir aplica r al cliente. Una restricción más leve no reemplaza a una
+// más dura que ya tenga (un bloqueado no pasa a requerir seña).
+func (s clienteService) Restringir(ctx context.Context, id string, r domain.RestriccionCliente) (*domain.Cliente, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r <= c.Restriccion {
+		return c, nil
+	}
+	c.Restriccion = r
+	if err := s.repo.GuardarRestriccion(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LevantarRestriccion deja al cliente reservar de nuevo sin condiciones. Las
+// ausencias previas quedan registradas pero no vuelven a contar para la regla.
+func (s clienteService) LevantarRestriccion(ctx context.Context, id string) (*domain.Cliente, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.Restriccion = domain.SinRestriccion
+	c.RestriccionLevantada = time.Now()
+	if err := s.repo.GuardarRestriccion(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Duplicados propone pares de clientes que pueden ser la misma persona: los
+// que tienen el mismo teléfono una vez normalizado y los de nombres parecidos.
+// Primero van los de mismo teléfono y después los más parecidos.
+func (s clienteService) Duplicados(ctx context.Context) ([]*domain.PosibleDuplicado, error) {
+	clientes, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	porID := make(map[string]*domain.Cliente, len(clientes))
+	porTelefono := make(map[string][]*domain.Cliente)
+	for _, c := range clientes {
+		if !c.Anonimizado.IsZero() {
+			continue
+		}
+		porID[c.ID] = c
+		telefono, err := domain.NormalizarTelefono(c.Telefono, s.codigoArea)
+		if err != nil {
+			telefono = c.Telefono
+		}
+		porTelefono[telefono] = append(porTelefono[telefono], c)
+	}
+
+	pares := make(map[[2]string]*domain.PosibleDuplicado)
+	par := func(a, b *domain.Cliente) *domain.PosibleDuplicado {
+		if a.ID > b.ID {
+			a, b = b, a
+		}
+		clave := [2]string{a.ID, b.ID}
+		if pares[clave] == nil {
+			pares[clave] = &domain.PosibleDuplicado{Clientes: [2]*domain.Cliente{a, b}}
+		}
+		return pares[clave]
+	}
+	for _, mismos := range porTelefono {
+		for i := range mismos {
+			for _, otro := range mismos[i+1:] {
+				par(mismos[i], otro).MismoTelefono = true
+			}
+		}
+	}
+	similares, err := s.repo.NombresSimilares(ctx, UmbralSimilitud)
+	if err != nil {
+		return nil, err
+	}
+	for _, sim := range similares {
+		a, b := porID[sim.IDs[0]], porID[sim.IDs[1]]
+		if a == nil || b == nil {
+			continue
+		}
+		par(a, b).Similitud = sim.Similitud
+	}
+
+	res := make([]*domain.PosibleDuplicado, 0, len(pares))
+	for _, p := range pares {
+		res = append(res, p)
+	}
+	slices.SortFunc(res, func(a, b *domain.PosibleDuplicado) int {
+		if a.MismoTelefono != b.MismoTelefono {
+			if a.MismoTelefono {
+				return -1
+			}
+			return 1
+		}
+		if c := cmp.Compare(b.Similitud, a.Similitud); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Clientes[0].Nombre, b.Clientes[0].Nombre)
+	})
+	return res, nil
+}
+
+// Fusionar deja un solo cliente a partir de un duplicado: el sobreviviente se
+// queda con todos sus turnos y la restricción más dura de los dos, y el
+// duplicado se borra. La fusión queda registrada en la auditoría.
+func (s clienteService) Fusionar(ctx context.Context, sobrevivienteID, duplicadoID string) (*domain.Fusion, error) {
+	if sobrevivienteID == duplicadoID {
+		return nil, domain.ErrFusionMismoCliente
+	}
+	sobreviviente, err := s.GetByID(ctx, sobrevivienteID)
+	if err != nil {
+		return nil, err
+	}
+	duplicado, err := s.GetByID(ctx, duplicadoID)
+	if err != nil {
+		return nil, err
+	}
+	if !sobreviviente.Anonimizado.IsZero() || !duplicado.Anonimizado.IsZero() {
+		return nil, domain.ErrClienteAnonimizado
+	}
+	if duplicado.Restriccion > sobreviviente.Restriccion {
+		sobreviviente.Restriccion = duplicado.Restriccion
+	}
+	f := &domain.Fusion{
+		ID:            uuid.New().String(),
+		Sobreviviente: sobreviviente,
+		Duplicado:     duplicado,
+		Fecha:         time.Now(),
+	}
+	if err := s.repo.Fusionar(ctx, f); err != nil {
+		return nil, err
+	}
+	// los totales de ausencias cambiaron con los turnos que se sumaron
+	if f.Sobreviviente, err = s.repo.GetByID(ctx, sobrevivienteID); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s clienteService) Auditoria(ctx context.Context, id string) ([]*domain.EntradaAuditoria, error) {
+	return s.repo.Auditoria(ctx, id)
+}
+
+// Exportar devuelve todo lo que se guarda del cliente, para entregárselo si lo
+// pide. Cada exportación queda en la auditoría.
+func (s clienteService) Exportar(ctx context.Context, id string) (*domain.ExportacionCliente, error) {
+	e, err := s.repo.Exportar(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	e.Fecha = time.Now()
+	if err := s.repo.RegistrarAuditoria(ctx, e.Auditoria(uuid.New().String())); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Anonimizar borra los datos personales del cliente cuando pide que lo
+// olviden. Sus turnos y pagos quedan, a nombre de un cliente anónimo que ya no
+// puede reservar.
+func (s clienteService) Anonimizar(ctx context.Context, id string) (*domain.Cliente, error) {
+	c, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Anonimizado.IsZero() {
+		return nil, domain.ErrClienteAnonimizado
+	}
+	a := &domain.Anonimizacion{ID: uuid.New().String(), ClienteID: id, Fecha: time.Now()}
+	// sin datos de contacto no se le puede avisar nada, así que sus turnos por
+	// venir se cancelan antes, con la agenda
+	pendientes, err := s.repo.TurnosPorVenir(ctx, id, a.Fecha)
+	if err != nil {
+		return nil, err
+	}
+	if len(pendientes) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTurnosPorVenir, strings.Join(pendientes, ", "))
+	}
+	if err := s.repo.Anonimizar(ctx, a); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
@@ -2,8 +2,8 @@ package cliente_test
 
 import (
 	"context"
-	"errors"
 	"testing"
+	"time"
 
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
 	cliente "github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/cliente"
@@ -49,6 +49,74 @@ func (m *MockClienteRepository) GetAll(ctx context.Context) ([]*domain.Cliente,
 	return nil, args.Error(1)
 }
 
+func (m *MockClienteRepository) GuardarRestriccion(ctx context.Context, c *domain.Cliente) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockClienteRepository) BuscarPorTelefono(ctx context.Context, telefono string) (*domain.Cliente, error) {
+	args := m.Called(ctx, telefono)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Cliente), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClienteRepository) Search(ctx context.Context, consulta string, limite int) ([]*domain.Cliente, error) {
+	args := m.Called(ctx, consulta, limite)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Cliente), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClienteRepository) NombresSimilares(ctx context.Context, umbral float64) ([]domain.NombresSimilares, error) {
+	args := m.Called(ctx, umbral)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.NombresSimilares), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClienteRepository) Fusionar(ctx context.Context, f *domain.Fusion) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *MockClienteRepository) Auditoria(ctx context.Context, clienteID string) ([]*domain.EntradaAuditoria, error) {
+	args := m.Called(ctx, clienteID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.EntradaAuditoria), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClienteRepository) RegistrarAuditoria(ctx context.Context, e *domain.EntradaAuditoria) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockClienteRepository) Exportar(ctx context.Context, id string) (*domain.ExportacionCliente, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.ExportacionCliente), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClienteRepository) Anonimizar(ctx context.Context, a *domain.Anonimizacion) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockClienteRepository) TurnosPorVenir(ctx context.Context, clienteID string, desde time.Time) ([]string, error) {
+	args := m.Called(ctx, clienteID, desde)
+	if args.Get(0) != nil {
+		return args.Get(0).([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func TestClienteService_Create(t *testing.T) {
 	t.Run("Error validate()", func(t *testing.T) {
 		s, _ := setupClienteServiceWithMock(t)
@@ -58,14 +126,17 @@ func TestClienteService_Create(t *testing.T) {
 		assert.EqualError(t, err, "campos no válidos")
 	})
 	t.Run("Asigna UUID  si ID esta vacío", func(t *testing.T) {
-		s, _, := setupClienteServiceWithMock(t)
-		res, err := s.Create(context.Background(), makeCliente("", "Ivan"))
+		s, mockRepo := setupClienteServiceWithMock(t)
+		nuevo := makeCliente("", "Ivan")
+		mockRepo.On("BuscarPorTelefono", mock.Anything, nuevo.Telefono).Return(nil, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+		res, err := s.Create(context.Background(), nuevo)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, res.ID)
 	})
 
 	tests := []struct {
-		name string
+		name     string
 		mockData *domain.Cliente
 		mockErr  error
 		WantErr  bool
@@ -76,6 +147,7 @@ func TestClienteService_Create(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s, mockRepo := setupClienteServiceWithMock(t)
+			mockRepo.On("BuscarPorTelefono", mock.Anything, tt.mockData.Telefono).Return(nil, nil)
 			mockRepo.On("CreateOrUpdate", mock.Anything, tt.mockData).Return(tt.mockData, tt.mockErr)
 			got, err := s.Create(context.Background(), tt.mockData)
 
@@ -108,10 +180,10 @@ func TestClienteService_Update(t *testing.T) {
 		assert.Nil(t, res)
 		assert.EqualError(t, err, "campos no válidos")
 	})
-	
+
 	//Table Driven Tests
 	tests := []struct {
-		name string
+		name     string
 		mockData *domain.Cliente
 		mockErr  error
 		WantErr  bool
@@ -122,6 +194,8 @@ func TestClienteService_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s, mockRepo := setupClienteServiceWithMock(t)
+			mockRepo.On("GetByID", mock.Anything, tt.mockData.ID).Return(makeCliente(tt.mockData.ID, "Pepe"), nil)
+			mockRepo.On("BuscarPorTelefono", mock.Anything, tt.mockData.Telefono).Return(nil, nil)
 			mockRepo.On("CreateOrUpdate", mock.Anything, tt.mockData).Return(tt.mockData, tt.mockErr)
 			got, err := s.Update(context.Background(), tt.mockData)
 
@@ -134,7 +208,227 @@ func TestClienteService_Update(t *testing.T) {
 
 			mockRepo.AssertExpectations(t)
 		})
-	}	
+	}
+}
+
+func TestClienteService_Telefono(t *testing.T) {
+	normalizados := []struct {
+		telefono string
+		want     string
+	}{
+		{"11 5555-1234", "+5491155551234"},
+		{"+54 9 11 5555-1234", "+5491155551234"},
+		{"+541155551234", "+5491155551234"},
+		{"15 5555-1234", "+5491155551234"},
+		{"5555-1234", "+5491155551234"},
+		{"011 15 5555-1234", "+5491155551234"},
+		{"(0351) 15 555-1234", "+5493515551234"},
+		{"0294 15 455-1234", "+5492944551234"},
+		{"+34 612 345 678", "+34612345678"},
+	}
+	for _, tt := range normalizados {
+		t.Run("Normaliza "+tt.telefono, func(t *testing.T) {
+			s, mockRepo := setupClienteServiceWithMock(t)
+			c := makeCliente("01", "Pepe")
+			c.Telefono = tt.telefono
+			mockRepo.On("BuscarPorTelefono", mock.Anything, tt.want).Return(nil, nil)
+			mockRepo.On("CreateOrUpdate", mock.Anything, c).Return(c, nil)
+
+			res, err := s.Create(context.Background(), c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, res.Telefono)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	for _, telefono := range []string{"1234", "11 5555-123", "11 5555-1234 int. 2", "+54 9 11 5555-12345", "+12"} {
+		t.Run("Inválido "+telefono, func(t *testing.T) {
+			s, mockRepo := setupClienteServiceWithMock(t)
+			c := makeCliente("01", "Pepe")
+			c.Telefono = telefono
+
+			res, err := s.Create(context.Background(), c)
+			assert.ErrorIs(t, err, domain.ErrTelefonoInvalido)
+			assert.Nil(t, res)
+			mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("Completa con el código de área configurado", func(t *testing.T) {
+		mockRepo := new(MockClienteRepository)
+		s := cliente.NewClienteService(mockRepo, "351")
+		c := makeCliente("01", "Pepe")
+		c.Telefono = "15 555-1234"
+		mockRepo.On("BuscarPorTelefono", mock.Anything, "+5493515551234").Return(nil, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, c).Return(c, nil)
+
+		res, err := s.Create(context.Background(), c)
+		assert.NoError(t, err)
+		assert.Equal(t, "+5493515551234", res.Telefono)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicado avisa de quién es", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		existente := makeCliente("01", "Pepe")
+		mockRepo.On("BuscarPorTelefono", mock.Anything, existente.Telefono).Return(existente, nil)
+
+		nuevo := makeCliente("", "José")
+		nuevo.Telefono = "11 5555 1234"
+		res, err := s.Create(context.Background(), nuevo)
+		assert.ErrorIs(t, err, domain.ErrTelefonoDuplicado)
+		var duplicado *domain.TelefonoDuplicadoError
+		if assert.ErrorAs(t, err, &duplicado) {
+			assert.Equal(t, existente, duplicado.Cliente)
+		}
+		assert.Contains(t, err.Error(), "Pepe (01)")
+		assert.Nil(t, res)
+
+		mockRepo.On("GetByID", mock.Anything, "02").Return(makeCliente("02", "José"), nil)
+		res, err = s.Update(context.Background(), makeCliente("02", "José"))
+		assert.ErrorAs(t, err, &duplicado)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("El mismo cliente conserva su teléfono", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		c := makeCliente("01", "Pepe")
+		mockRepo.On("GetByID", mock.Anything, "01").Return(makeCliente("01", "Pepe"), nil)
+		mockRepo.On("BuscarPorTelefono", mock.Anything, c.Telefono).Return(makeCliente("01", "Pepe"), nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, c).Return(c, nil)
+
+		_, err := s.Update(context.Background(), c)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicado al guardar", func(t *testing.T) {
+		// otro cliente se registró con el teléfono entre la verificación y el guardado
+		s, mockRepo := setupClienteServiceWithMock(t)
+		c := makeCliente("02", "José")
+		existente := makeCliente("01", "Pepe")
+		mockRepo.On("BuscarPorTelefono", mock.Anything, c.Telefono).Return(nil, nil).Once()
+		mockRepo.On("CreateOrUpdate", mock.Anything, c).Return(nil, domain.ErrTelefonoDuplicado)
+		mockRepo.On("BuscarPorTelefono", mock.Anything, c.Telefono).Return(existente, nil).Once()
+
+		res, err := s.Create(context.Background(), c)
+		var duplicado *domain.TelefonoDuplicadoError
+		if assert.ErrorAs(t, err, &duplicado) {
+			assert.Equal(t, existente, duplicado.Cliente)
+		}
+		assert.Nil(t, res)
+	})
+}
+
+func TestClienteService_Search(t *testing.T) {
+	t.Run("Consulta corta", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		res, err := s.Search(context.Background(), "  m ", 0)
+		assert.ErrorIs(t, err, domain.ErrBusquedaCorta)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	tests := []struct {
+		name       string
+		limite     int
+		wantLimite int
+	}{
+		{"Sin límite usa el máximo", 0, cliente.MaxResultadosBusqueda},
+		{"Límite pedido", 5, 5},
+		{"Límite excesivo se acota", 500, cliente.MaxResultadosBusqueda},
+		{"Límite negativo usa el máximo", -1, cliente.MaxResultadosBusqueda},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setupClienteServiceWithMock(t)
+			encontrados := []*domain.Cliente{makeCliente("01", "María José")}
+			mockRepo.On("Search", mock.Anything, "maria", tt.wantLimite).Return(encontrados, nil)
+
+			res, err := s.Search(context.Background(), " maria ", tt.limite)
+			assert.NoError(t, err)
+			assert.Equal(t, encontrados, res)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClienteService_Duplicados(t *testing.T) {
+	s, mockRepo := setupClienteServiceWithMock(t)
+	maria := makeCliente("01", "María José")
+	mariaTelefono := makeCliente("02", "Maria Jose")
+	mariaTelefono.Telefono = "11 5555-1234" // cargado antes de normalizar
+	pedro := makeCliente("03", "Pedro")
+	pedro.Telefono = "+5493515551234"
+	pedroGomez := makeCliente("04", "Pedro Gómez")
+	pedroGomez.Telefono = "+5493515559999"
+	otroTelefono := makeCliente("05", "Juana")
+	otroTelefono.Telefono = "+5493515559999"
+	mockRepo.On("GetAll", mock.Anything).Return([]*domain.Cliente{pedroGomez, maria, pedro, otroTelefono, mariaTelefono}, nil)
+	mockRepo.On("NombresSimilares", mock.Anything, cliente.UmbralSimilitud).Return([]domain.NombresSimilares{
+		{IDs: [2]string{"03", "04"}, Similitud: 0.6},
+		{IDs: [2]string{"01", "02"}, Similitud: 0.9},
+		{IDs: [2]string{"01", "99"}, Similitud: 0.7}, // borrado entre las dos consultas
+	}, nil)
+
+	res, err := s.Duplicados(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.PosibleDuplicado{
+		{Clientes: [2]*domain.Cliente{maria, mariaTelefono}, MismoTelefono: true, Similitud: 0.9},
+		{Clientes: [2]*domain.Cliente{pedroGomez, otroTelefono}, MismoTelefono: true},
+		{Clientes: [2]*domain.Cliente{pedro, pedroGomez}, Similitud: 0.6},
+	}, res)
+}
+
+func TestClienteService_Fusionar(t *testing.T) {
+	t.Run("Consigo mismo", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		res, err := s.Fusionar(context.Background(), "01", "01")
+		assert.ErrorIs(t, err, domain.ErrFusionMismoCliente)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "Fusionar", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Duplicado inexistente", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		mockRepo.On("GetByID", mock.Anything, "01").Return(makeCliente("01", "María José"), nil)
+		mockRepo.On("GetByID", mock.Anything, "02").Return(nil, assert.AnError)
+		res, err := s.Fusionar(context.Background(), "01", "02")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "Fusionar", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Pasa todo al sobreviviente", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		sobreviviente := makeCliente("01", "María José")
+		duplicado := makeCliente("02", "Maria Jose")
+		duplicado.Restriccion = domain.RequiereSena
+		actualizado := makeCliente("01", "María José")
+		actualizado.Ausencias = 3
+		actualizado.Restriccion = domain.RequiereSena
+		mockRepo.On("GetByID", mock.Anything, "01").Return(sobreviviente, nil).Once()
+		mockRepo.On("GetByID", mock.Anything, "02").Return(duplicado, nil).Once()
+		mockRepo.On("Fusionar", mock.Anything, mock.MatchedBy(func(f *domain.Fusion) bool {
+			return f.ID != "" && f.Sobreviviente.Restriccion == domain.RequiereSena && f.Duplicado == duplicado
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Fusion).Movidos = map[string]int64{"turno": 3}
+		}).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, "01").Return(actualizado, nil).Once()
+
+		res, err := s.Fusionar(context.Background(), "01", "02")
+		assert.NoError(t, err)
+		assert.Equal(t, actualizado, res.Sobreviviente)
+		assert.Equal(t, duplicado, res.Duplicado)
+		assert.Equal(t, map[string]int64{"turno": 3}, res.Movidos)
+
+		entrada := res.Auditoria()
+		assert.Equal(t, domain.AccionFusion, entrada.Accion)
+		assert.Equal(t, "01", entrada.ClienteID)
+		assert.Equal(t, "02", entrada.Detalle["duplicado"].(map[string]any)["id"])
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestClienteService_Delete(t *testing.T) {
@@ -146,6 +440,7 @@ func TestClienteService_Delete(t *testing.T) {
 	}{
 		{"Success", "123", nil, false},
 		{"RepoError", "123", assert.AnError, true},
+		{"Con turnos", "123", domain.ErrClienteConTurnos, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -174,13 +469,15 @@ func TestClienteService_GetByID(t *testing.T) {
 	}{
 		{"Success", "123", makeCliente("123", "Pepe"), nil, false},
 		{"RepoError", "123", nil, assert.AnError, true},
-		{"NotFound", "123", nil, nil, false}, //simula no encontrado
-		{"EmptyID", "", makeCliente("", "Pepe"), assert.AnError, true},   //simula id vacío
+		{"NotFound", "123", nil, nil, false},                           //simula no encontrado
+		{"EmptyID", "", makeCliente("", "Pepe"), assert.AnError, true}, //simula id vacío
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s, mockRepo := setupClienteServiceWithMock(t)
-			mockRepo.On("GetByID", mock.Anything, tt.mockID).Return(tt.mockData, tt.mockErr)
+			if tt.mockID != "" { // con ID vacío el servicio corta antes de llegar al repositorio
+				mockRepo.On("GetByID", mock.Anything, tt.mockID).Return(tt.mockData, tt.mockErr)
+			}
 			got, err := s.GetByID(context.Background(), tt.mockID)
 
 			if tt.WantErr {
@@ -192,7 +489,127 @@ func TestClienteService_GetByID(t *testing.T) {
 
 			mockRepo.AssertExpectations(t)
 		})
-	}	
+	}
+}
+
+func TestClienteService_Restriccion(t *testing.T) {
+	t.Run("Restringir guarda la nueva restricción", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		c := makeCliente("01", "Pepe")
+		mockRepo.On("GetByID", mock.Anything, "01").Return(c, nil)
+		mockRepo.On("GuardarRestriccion", mock.Anything, c).Return(nil)
+
+		got, err := s.Restringir(context.Background(), "01", domain.Bloqueado)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Bloqueado, got.Restriccion)
+		mockRepo.AssertExpectations(t)
+	})
+	t.Run("Restringir no afloja una restricción más dura", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		c := makeCliente("01", "Pepe")
+		c.Restriccion = domain.Bloqueado
+		mockRepo.On("GetByID", mock.Anything, "01").Return(c, nil)
+
+		got, err := s.Restringir(context.Background(), "01", domain.RequiereSena)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Bloqueado, got.Restriccion)
+		mockRepo.AssertNotCalled(t, "GuardarRestriccion", mock.Anything, mock.Anything)
+	})
+	t.Run("LevantarRestriccion registra cuándo se levantó", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		c := makeCliente("01", "Pepe")
+		c.Restriccion = domain.Bloqueado
+		mockRepo.On("GetByID", mock.Anything, "01").Return(c, nil)
+		mockRepo.On("GuardarRestriccion", mock.Anything, c).Return(nil)
+
+		got, err := s.LevantarRestriccion(context.Background(), "01")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SinRestriccion, got.Restriccion)
+		assert.False(t, got.RestriccionLevantada.IsZero())
+	})
+}
+
+func TestClienteService_Exportar(t *testing.T) {
+	t.Run("Registra la exportación", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		exportacion := &domain.ExportacionCliente{
+			ClienteID: "01",
+			Cliente:   map[string]any{"id": "01", "nombre": "Pepe"},
+			Tablas: map[string][]map[string]any{
+				"turno": {{"id": "t1"}, {"id": "t2"}},
+				"ficha": {},
+			},
+		}
+		mockRepo.On("Exportar", mock.Anything, "01").Return(exportacion, nil)
+		mockRepo.On("RegistrarAuditoria", mock.Anything, mock.MatchedBy(func(e *domain.EntradaAuditoria) bool {
+			return e.ID != "" && e.Accion == domain.AccionExportacion && e.ClienteID == "01" &&
+				assert.ObjectsAreEqual(map[string]int{"turno": 2, "ficha": 0}, e.Detalle["filas"])
+		})).Return(nil)
+
+		res, err := s.Exportar(context.Background(), "01")
+		assert.NoError(t, err)
+		assert.Equal(t, exportacion, res)
+		assert.False(t, res.Fecha.IsZero())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Sin auditoría no se entrega", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		mockRepo.On("Exportar", mock.Anything, "01").Return(&domain.ExportacionCliente{ClienteID: "01"}, nil)
+		mockRepo.On("RegistrarAuditoria", mock.Anything, mock.Anything).Return(assert.AnError)
+
+		res, err := s.Exportar(context.Background(), "01")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, res)
+	})
+}
+
+func TestClienteService_Anonimizar(t *testing.T) {
+	t.Run("Anonimiza y devuelve el cliente actualizado", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		anonimo := makeCliente("01", domain.NombreAnonimizado)
+		anonimo.Anonimizado = time.Now()
+		mockRepo.On("GetByID", mock.Anything, "01").Return(makeCliente("01", "Pepe"), nil).Once()
+		mockRepo.On("TurnosPorVenir", mock.Anything, "01", mock.AnythingOfType("time.Time")).Return([]string{}, nil)
+		mockRepo.On("Anonimizar", mock.Anything, mock.MatchedBy(func(a *domain.Anonimizacion) bool {
+			return a.ID != "" && a.ClienteID == "01" && !a.Fecha.IsZero()
+		})).Return(nil)
+		mockRepo.On("GetByID", mock.Anything, "01").Return(anonimo, nil).Once()
+
+		res, err := s.Anonimizar(context.Background(), "01")
+		assert.NoError(t, err)
+		assert.Equal(t, anonimo, res)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Con turnos por venir no anonimiza", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		mockRepo.On("GetByID", mock.Anything, "01").Return(makeCliente("01", "Pepe"), nil)
+		mockRepo.On("TurnosPorVenir", mock.Anything, "01", mock.AnythingOfType("time.Time")).Return([]string{"t1", "t2"}, nil)
+
+		res, err := s.Anonimizar(context.Background(), "01")
+		assert.ErrorIs(t, err, domain.ErrTurnosPorVenir)
+		assert.ErrorContains(t, err, "t1, t2")
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "Anonimizar", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Ya anonimizado", func(t *testing.T) {
+		s, mockRepo := setupClienteServiceWithMock(t)
+		anonimo := makeCliente("01", domain.NombreAnonimizado)
+		anonimo.Anonimizado = time.Now()
+		mockRepo.On("GetByID", mock.Anything, "01").Return(anonimo, nil)
+
+		_, err := s.Anonimizar(context.Background(), "01")
+		assert.ErrorIs(t, err, domain.ErrClienteAnonimizado)
+
+		// tampoco se le pueden volver a cargar datos ni reservar
+		_, err = s.Update(context.Background(), makeCliente("01", "Pepe"))
+		assert.ErrorIs(t, err, domain.ErrClienteAnonimizado)
+		assert.ErrorIs(t, anonimo.PuedeReservar(0, 0), domain.ErrClienteAnonimizado)
+		mockRepo.AssertNotCalled(t, "Anonimizar", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
 }
 
 // funciones auxiliares
@@ -200,14 +617,14 @@ func makeCliente(id string, name string) *domain.Cliente {
 	return &domain.Cliente{
 		ID:                 id,
 		Nombre:             name,
-		Telefono:           "123456789",
+		Telefono:           "+5491155551234",
 		PreferenciaHoraria: domain.PreferenciaHoraria(1),
 	}
 }
 
 func setupClienteServiceWithMock(t *testing.T) (cliente.ClienteService, *MockClienteRepository) {
 	mockRepo := new(MockClienteRepository)
-	s := cliente.NewClienteService(mockRepo)
+	s := cliente.NewClienteService(mockRepo, "11")
 	return s, mockRepo
 }
 
@@ -0,0 +1,88 @@
+package ficha
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
+	"github.com/google/uuid"
+)
+
+type FichaService interface {
+	Create(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error)
+	Update(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error)
+	Delete(ctx context.Context, clienteID, id string) error
+	GetByID(ctx context.Context, clienteID, id string) (*domain.EntradaFicha, error)
+	GetByCliente(ctx context.Context, clienteID string) ([]*domain.EntradaFicha, error)
+}
+
+type fichaService struct {
+	repo   repository.FichaRepository
+	turnos repository.TurnoRepository // para controlar de quién es el turno de cada entrada
+	zona   *time.Location             // para saber qué día es hoy
+}
+
+func NewFichaService(repo repository.FichaRepository, turnos repository.TurnoRepository, zona *time.Location) *fichaService {
+	return &fichaService{repo: repo, turnos: turnos, zona: zona}
+}
+
+// Create agrega una entrada a la ficha; sin fecha, se la toma como de hoy.
+func (s fichaService) Create(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error) {
+	if e.Fecha.IsZero() {
+		e.Fecha, _ = domain.Separar(time.Now(), s.zona)
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if err := s.verificarTurno(ctx, e); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrUpdate(ctx, e)
+}
+
+func (s fichaService) Update(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error) {
+	if e.ID == "" {
+		return nil, errors.New("ID requerido para actualizar")
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.verificarTurno(ctx, e); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrUpdate(ctx, e)
+}
+
+// verificarTurno devuelve domain.ErrTurnoDeOtroCliente si e se refiere a un
+// turno que no existe o que es de otro cliente.
+func (s fichaService) verificarTurno(ctx context.Context, e *domain.EntradaFicha) error {
+	if e.TurnoID == "" {
+		return nil
+	}
+	t, err := s.turnos.GetByID(ctx, e.TurnoID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && t.Cliente.ID != e.ClienteID) {
+		return domain.ErrTurnoDeOtroCliente
+	}
+	return err
+}
+
+func (s fichaService) Delete(ctx context.Context, clienteID, id string) error {
+	return s.repo.Delete(ctx, clienteID, id)
+}
+
+func (s fichaService) GetByID(ctx context.Context, clienteID, id string) (*domain.EntradaFicha, error) {
+	if id == "" {
+		return nil, errors.New("ID requerido para obtener la entrada de la ficha")
+	}
+	return s.repo.GetByID(ctx, clienteID, id)
+}
+
+func (s fichaService) GetByCliente(ctx context.Context, clienteID string) ([]*domain.EntradaFicha, error) {
+	return s.repo.GetByCliente(ctx, clienteID)
+}
@@ -0,0 +1,219 @@
+package ficha_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/ficha"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFichaRepository struct {
+	mock.Mock
+}
+
+func (m *MockFichaRepository) CreateOrUpdate(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error) {
+	args := m.Called(ctx, e)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFichaRepository) Delete(ctx context.Context, clienteID, id string) error {
+	args := m.Called(ctx, clienteID, id)
+	return args.Error(0)
+}
+
+func (m *MockFichaRepository) GetByID(ctx context.Context, clienteID, id string) (*domain.EntradaFicha, error) {
+	args := m.Called(ctx, clienteID, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFichaRepository) GetByCliente(ctx context.Context, clienteID string) ([]*domain.EntradaFicha, error) {
+	args := m.Called(ctx, clienteID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFichaRepository) UltimasFormulas(ctx context.Context, clienteIDs []string) (map[string]*domain.EntradaFicha, error) {
+	args := m.Called(ctx, clienteIDs)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[string]*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockTurnoRepository struct {
+	repository.TurnoRepository
+	mock.Mock
+}
+
+func (m *MockTurnoRepository) GetByID(ctx context.Context, id string) (*domain.Turno, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Turno), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestFichaService_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		cambiar func(e *domain.EntradaFicha)
+		wantErr string
+	}{
+		{"Sin cliente", func(e *domain.EntradaFicha) { e.ClienteID = "" }, "cliente y fecha requeridos"},
+		{"Oxidante negativo", func(e *domain.EntradaFicha) { e.Oxidante = -10 }, "volumen de oxidante inválido"},
+		{"Oxidante excesivo", func(e *domain.EntradaFicha) { e.Oxidante = 50 }, "volumen de oxidante inválido"},
+		{"Pose negativa", func(e *domain.EntradaFicha) { e.TiempoPose = -time.Minute }, "tiempo de pose inválido"},
+		{"Vacía", func(e *domain.EntradaFicha) { *e = domain.EntradaFicha{ClienteID: "01"} }, "la entrada de la ficha está vacía"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setupFichaServiceWithMock(t)
+			e := makeEntrada("")
+			tt.cambiar(e)
+			res, err := s.Create(context.Background(), e)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, res)
+			mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("Asigna ID y fecha de hoy", func(t *testing.T) {
+		s, mockRepo := setupFichaServiceWithMock(t)
+		e := makeEntrada("")
+		e.Fecha = time.Time{}
+		mockRepo.On("CreateOrUpdate", mock.Anything, e).Return(e, nil)
+
+		res, err := s.Create(context.Background(), e)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.ID)
+		hoy, _ := domain.Separar(time.Now(), time.UTC)
+		assert.Equal(t, hoy, res.Fecha)
+		mockRepo.AssertExpectations(t)
+	})
+
+	turnos := []struct {
+		name    string
+		turno   *domain.Turno
+		err     error
+		wantErr error
+	}{
+		{"Turno del cliente", &domain.Turno{ID: "t9", Cliente: domain.Cliente{ID: "01"}}, nil, nil},
+		{"Turno de otro cliente", &domain.Turno{ID: "t9", Cliente: domain.Cliente{ID: "02"}}, nil, domain.ErrTurnoDeOtroCliente},
+		{"Turno que no existe", nil, sql.ErrNoRows, domain.ErrTurnoDeOtroCliente},
+	}
+	for _, tt := range turnos {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo, mockTurnos := setupFichaServiceConTurnos(t)
+			e := makeEntrada("f1")
+			e.TurnoID = "t9"
+			mockTurnos.On("GetByID", mock.Anything, "t9").Return(tt.turno, tt.err)
+			if tt.wantErr == nil {
+				mockRepo.On("CreateOrUpdate", mock.Anything, e).Return(e, nil)
+			}
+
+			res, err := s.Create(context.Background(), e)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, res)
+				mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			mockTurnos.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFichaService_Update(t *testing.T) {
+	t.Run("Return error si ID está vacío", func(t *testing.T) {
+		s, _ := setupFichaServiceWithMock(t)
+		res, err := s.Update(context.Background(), makeEntrada(""))
+		assert.EqualError(t, err, "ID requerido para actualizar")
+		assert.Nil(t, res)
+	})
+
+	t.Run("Requiere fecha", func(t *testing.T) {
+		s, _ := setupFichaServiceWithMock(t)
+		e := makeEntrada("f1")
+		e.Fecha = time.Time{}
+		_, err := s.Update(context.Background(), e)
+		assert.EqualError(t, err, "cliente y fecha requeridos")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		s, mockRepo := setupFichaServiceWithMock(t)
+		e := makeEntrada("f1")
+		mockRepo.On("CreateOrUpdate", mock.Anything, e).Return(e, nil)
+		res, err := s.Update(context.Background(), e)
+		assert.NoError(t, err)
+		assert.Equal(t, e, res)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No pasa la entrada a un turno de otro cliente", func(t *testing.T) {
+		s, mockRepo, mockTurnos := setupFichaServiceConTurnos(t)
+		e := makeEntrada("f1")
+		e.TurnoID = "t9"
+		mockTurnos.On("GetByID", mock.Anything, "t9").Return(&domain.Turno{ID: "t9", Cliente: domain.Cliente{ID: "02"}}, nil)
+
+		res, err := s.Update(context.Background(), e)
+		assert.ErrorIs(t, err, domain.ErrTurnoDeOtroCliente)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFichaService_GetByCliente(t *testing.T) {
+	s, mockRepo := setupFichaServiceWithMock(t)
+	entradas := []*domain.EntradaFicha{makeEntrada("f2"), makeEntrada("f1")}
+	mockRepo.On("GetByCliente", mock.Anything, "01").Return(entradas, nil)
+
+	res, err := s.GetByCliente(context.Background(), "01")
+	assert.NoError(t, err)
+	assert.Equal(t, entradas, res)
+
+	_, err = s.GetByID(context.Background(), "01", "")
+	assert.Error(t, err)
+}
+
+// funciones auxiliares
+func makeEntrada(id string) *domain.EntradaFicha {
+	return &domain.EntradaFicha{
+		ID:          id,
+		ClienteID:   "01",
+		Fecha:       time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+		Marca:       "Igora",
+		Formula:     "30 g 7.1 + 30 g 7.0",
+		Oxidante:    20,
+		TiempoPose:  35 * time.Minute,
+		TipoCabello: "fino, con canas",
+		Alergias:    "PPD",
+	}
+}
+
+func setupFichaServiceWithMock(t *testing.T) (ficha.FichaService, *MockFichaRepository) {
+	s, mockRepo, _ := setupFichaServiceConTurnos(t)
+	return s, mockRepo
+}
+
+func setupFichaServiceConTurnos(t *testing.T) (ficha.FichaService, *MockFichaRepository, *MockTurnoRepository) {
+	mockRepo := new(MockFichaRepository)
+	mockTurnos := new(MockTurnoRepository)
+	s := ficha.NewFichaService(mockRepo, mockTurnos, time.UTC)
+	return s, mockRepo, mockTurnos
+}
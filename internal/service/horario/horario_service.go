@@ -0,0 +1,48 @@
+package horario
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
+)
+
+type HorarioService interface {
+	Update(ctx context.Context, h *domain.HorarioLaboral) (*domain.HorarioLaboral, error)
+	GetByDia(ctx context.Context, dia time.Weekday) (*domain.HorarioLaboral, error)
+	GetAll(ctx context.Context) ([]*domain.HorarioLaboral, error)
+	// Cubre indica si el turno entra completo dentro del horario de su día.
+	Cubre(ctx context.Context, t *domain.Turno) (bool, error)
+}
+
+type horarioService struct {
+	repo repository.HorarioLaboralRepository
+}
+
+func NewHorarioService(repo repository.HorarioLaboralRepository) *horarioService {
+	return &horarioService{repo: repo}
+}
+
+func (s horarioService) Update(ctx context.Context, h *domain.HorarioLaboral) (*domain.HorarioLaboral, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrUpdate(ctx, h)
+}
+
+func (s horarioService) GetByDia(ctx context.Context, dia time.Weekday) (*domain.HorarioLaboral, error) {
+	return s.repo.GetByDia(ctx, dia)
+}
+
+func (s horarioService) GetAll(ctx context.Context) ([]*domain.HorarioLaboral, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s horarioService) Cubre(ctx context.Context, t *domain.Turno) (bool, error) {
+	h, err := s.repo.GetByDia(ctx, t.Fecha.Weekday())
+	if err != nil {
+		return false, err
+	}
+	return h.Cubre(t.Hora, t.Duracion), nil
+}
@@ -0,0 +1,123 @@
+package horario_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/horario"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHorarioLaboralRepository struct {
+	mock.Mock
+}
+
+func (m *MockHorarioLaboralRepository) CreateOrUpdate(ctx context.Context, h *domain.HorarioLaboral) (*domain.HorarioLaboral, error) {
+	args := m.Called(ctx, h)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.HorarioLaboral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockHorarioLaboralRepository) GetByDia(ctx context.Context, dia time.Weekday) (*domain.HorarioLaboral, error) {
+	args := m.Called(ctx, dia)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.HorarioLaboral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockHorarioLaboralRepository) GetAll(ctx context.Context) ([]*domain.HorarioLaboral, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.HorarioLaboral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestHorarioService_Update(t *testing.T) {
+	t.Run("Error si los intervalos se superponen", func(t *testing.T) {
+		s, _ := setupHorarioServiceWithMock(t)
+		h := domain.NewHorarioLaboral(time.Monday, []domain.IntervaloHorario{
+			intervalo(9, 0, 13, 0),
+			intervalo(12, 0, 16, 0),
+		})
+		res, err := s.Update(context.Background(), h)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+	t.Run("Error si el intervalo termina antes de empezar", func(t *testing.T) {
+		s, _ := setupHorarioServiceWithMock(t)
+		h := domain.NewHorarioLaboral(time.Monday, []domain.IntervaloHorario{intervalo(13, 0, 9, 0)})
+		res, err := s.Update(context.Background(), h)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+	t.Run("Success ordena los intervalos", func(t *testing.T) {
+		s, mockRepo := setupHorarioServiceWithMock(t)
+		h := domain.NewHorarioLaboral(time.Monday, []domain.IntervaloHorario{
+			intervalo(16, 0, 20, 0),
+			intervalo(9, 0, 13, 0),
+		})
+		mockRepo.On("CreateOrUpdate", mock.Anything, h).Return(h, nil)
+		res, err := s.Update(context.Background(), h)
+		assert.NoError(t, err)
+		assert.Equal(t, 9, res.Intervalos[0].Desde.Hour)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestHorarioService_Cubre(t *testing.T) {
+	lunes, _ := time.Parse("2006/01/02", "2025/06/02")
+	semanaPartida := domain.NewHorarioLaboral(time.Monday, []domain.IntervaloHorario{
+		intervalo(9, 0, 13, 0),
+		intervalo(16, 0, 20, 0),
+	})
+	tests := []struct {
+		name     string
+		hora     domain.TimeOfDay
+		duracion time.Duration
+		want     bool
+	}{
+		{"Dentro de la mañana", domain.TimeOfDay{Hour: 9, Minute: 0}, time.Hour, true},
+		{"Termina justo al cierre", domain.TimeOfDay{Hour: 12, Minute: 30}, 30 * time.Minute, true},
+		{"Cruza el corte del mediodía", domain.TimeOfDay{Hour: 12, Minute: 30}, time.Hour, false},
+		{"Antes de abrir", domain.TimeOfDay{Hour: 8, Minute: 30}, time.Hour, false},
+		{"Dentro de la tarde", domain.TimeOfDay{Hour: 18, Minute: 0}, time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setupHorarioServiceWithMock(t)
+			mockRepo.On("GetByDia", mock.Anything, time.Monday).Return(semanaPartida, nil)
+			got, err := s.Cubre(context.Background(), &domain.Turno{Fecha: lunes, Hora: tt.hora, Duracion: tt.duracion})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	t.Run("Día sin franjas está cerrado", func(t *testing.T) {
+		s, mockRepo := setupHorarioServiceWithMock(t)
+		domingo := lunes.AddDate(0, 0, -1)
+		mockRepo.On("GetByDia", mock.Anything, time.Sunday).Return(domain.NewHorarioLaboral(time.Sunday, nil), nil)
+		got, err := s.Cubre(context.Background(), &domain.Turno{Fecha: domingo, Hora: domain.TimeOfDay{Hour: 10}, Duracion: time.Hour})
+		assert.NoError(t, err)
+		assert.False(t, got)
+	})
+}
+
+// funciones auxiliares
+func intervalo(h1, m1, h2, m2 int) domain.IntervaloHorario {
+	return domain.IntervaloHorario{
+		Desde: domain.TimeOfDay{Hour: h1, Minute: m1},
+		Hasta: domain.TimeOfDay{Hour: h2, Minute: m2},
+	}
+}
+
+func setupHorarioServiceWithMock(t *testing.T) (horario.HorarioService, *MockHorarioLaboralRepository) {
+	mockRepo := new(MockHorarioLaboralRepository)
+	s := horario.NewHorarioService(mockRepo)
+	return s, mockRepo
+}
@@ -0,0 +1,61 @@
+package servicio
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
+	"github.com/google/uuid"
+)
+
+type ServicioService interface {
+	Create(ctx context.Context, s *domain.Servicio) (*domain.Servicio, error)
+	Update(ctx context.Context, s *domain.Servicio) (*domain.Servicio, error)
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (*domain.Servicio, error)
+	GetAll(ctx context.Context) ([]*domain.Servicio, error)
+}
+
+type servicioService struct {
+	repo repository.ServicioRepository
+}
+
+func NewServicioService(repo repository.ServicioRepository) *servicioService {
+	return &servicioService{repo: repo}
+}
+
+func (s servicioService) Create(ctx context.Context, sv *domain.Servicio) (*domain.Servicio, error) {
+	if err := sv.Validate(); err != nil {
+		return nil, err
+	}
+	if sv.ID == "" {
+		sv.ID = uuid.New().String()
+	}
+	return s.repo.CreateOrUpdate(ctx, sv)
+}
+
+func (s servicioService) Update(ctx context.Context, sv *domain.Servicio) (*domain.Servicio, error) {
+	if sv.ID == "" {
+		return nil, errors.New("ID requerido para actualizar")
+	}
+	if err := sv.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrUpdate(ctx, sv)
+}
+
+func (s servicioService) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s servicioService) GetByID(ctx context.Context, id string) (*domain.Servicio, error) {
+	if id == "" {
+		return nil, errors.New("ID requerido para obtener servicio")
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s servicioService) GetAll(ctx context.Context) ([]*domain.Servicio, error) {
+	return s.repo.GetAll(ctx)
+}
@@ -0,0 +1,182 @@
+package servicio_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/servicio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockServicioRepository struct {
+	mock.Mock
+}
+
+func (m *MockServicioRepository) CreateOrUpdate(ctx context.Context, s *domain.Servicio) (*domain.Servicio, error) {
+	args := m.Called(ctx, s)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Servicio), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockServicioRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockServicioRepository) GetByID(ctx context.Context, id string) (*domain.Servicio, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Servicio), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockServicioRepository) GetAll(ctx context.Context) ([]*domain.Servicio, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Servicio), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestServicioService_Create(t *testing.T) {
+	t.Run("Error validate()", func(t *testing.T) {
+		s, _ := setupServicioServiceWithMock(t)
+		sv := makeServicio("01", "Corte")
+		sv.Duracion = 0
+		res, err := s.Create(context.Background(), sv)
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "campos no válidos")
+	})
+	t.Run("Margen mayor al máximo", func(t *testing.T) {
+		s, _ := setupServicioServiceWithMock(t)
+		sv := makeServicio("01", "Color")
+		limpieza := domain.MargenMaximo + time.Minute
+		sv.MargenDespues = &limpieza
+		res, err := s.Create(context.Background(), sv)
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "margen inválido")
+	})
+	t.Run("Fases inválidas", func(t *testing.T) {
+		s, _ := setupServicioServiceWithMock(t)
+		trabajo := func(m int) domain.Fase { return domain.Fase{Duracion: time.Duration(m) * time.Minute} }
+		pose := func(m int) domain.Fase { return domain.Fase{Duracion: time.Duration(m) * time.Minute, Libre: true} }
+		for _, fases := range [][]domain.Fase{
+			{pose(10), trabajo(20)},
+			{trabajo(10), pose(20)},
+			{trabajo(10), trabajo(20)},
+			{trabajo(10), pose(10), trabajo(5)}, // no suman la duración
+		} {
+			sv := makeServicio("01", "Color")
+			sv.Duracion = 30 * time.Minute
+			sv.Fases = fases
+			res, err := s.Create(context.Background(), sv)
+			assert.Nil(t, res)
+			assert.Error(t, err)
+		}
+	})
+	t.Run("Asigna UUID si ID esta vacío", func(t *testing.T) {
+		s, mockRepo := setupServicioServiceWithMock(t)
+		nuevo := makeServicio("", "Corte")
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+		res, err := s.Create(context.Background(), nuevo)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.ID)
+	})
+
+	tests := []struct {
+		name     string
+		mockData *domain.Servicio
+		mockErr  error
+		WantErr  bool
+	}{
+		{"Success", makeServicio("01", "Corte"), nil, false},
+		{"RepoError", makeServicio("01", "Corte"), assert.AnError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setupServicioServiceWithMock(t)
+			mockRepo.On("CreateOrUpdate", mock.Anything, tt.mockData).Return(tt.mockData, tt.mockErr)
+			got, err := s.Create(context.Background(), tt.mockData)
+
+			if tt.WantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockData, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServicioService_Update(t *testing.T) {
+	t.Run("Return error si ID está vacío", func(t *testing.T) {
+		s, _ := setupServicioServiceWithMock(t)
+		res, err := s.Update(context.Background(), makeServicio("", "Corte"))
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "ID requerido para actualizar")
+	})
+	t.Run("Error validate()", func(t *testing.T) {
+		s, _ := setupServicioServiceWithMock(t)
+		res, err := s.Update(context.Background(), makeServicio("01", ""))
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "campos no válidos")
+	})
+	t.Run("Success", func(t *testing.T) {
+		s, mockRepo := setupServicioServiceWithMock(t)
+		sv := makeServicio("01", "Corte")
+		mockRepo.On("CreateOrUpdate", mock.Anything, sv).Return(sv, nil)
+		got, err := s.Update(context.Background(), sv)
+		assert.NoError(t, err)
+		assert.Equal(t, sv, got)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestServicioService_GetByID(t *testing.T) {
+	tests := []struct {
+		name     string
+		mockID   string
+		mockData *domain.Servicio
+		mockErr  error
+		WantErr  bool
+	}{
+		{"Success", "01", makeServicio("01", "Corte"), nil, false},
+		{"RepoError", "01", nil, assert.AnError, true},
+		{"EmptyID", "", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setupServicioServiceWithMock(t)
+			if tt.mockID != "" {
+				mockRepo.On("GetByID", mock.Anything, tt.mockID).Return(tt.mockData, tt.mockErr)
+			}
+			got, err := s.GetByID(context.Background(), tt.mockID)
+
+			if tt.WantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockData, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// funciones auxiliares
+func makeServicio(id, nombre string) *domain.Servicio {
+	return domain.NewServicio(id, nombre, 45*time.Minute, 8000, true)
+}
+
+func setupServicioServiceWithMock(t *testing.T) (servicio.ServicioService, *MockServicioRepository) {
+	mockRepo := new(MockServicioRepository)
+	s := servicio.NewServicioService(mockRepo)
+	return s, mockRepo
+}
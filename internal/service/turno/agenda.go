@@ -0,0 +1,88 @@
+package turno
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+// Agenda devuelve los turnos de desde a hasta agrupados por día, con los datos
+// del cliente y la ocupación de cada día. Los cancelados no aparecen.
+func (s turnoService) Agenda(ctx context.Context, desde, hasta time.Time) (*domain.Agenda, error) {
+	if hasta.Before(desde) {
+		return nil, errors.New("rango de fechas inválido")
+	}
+	turnos, err := s.repo.GetEntreFechas(ctx, desde, hasta, domain.EstadosQueOcupan...)
+	if err != nil {
+		return nil, err
+	}
+	franjas, err := s.franjasPorDia(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bloqueos, err := s.bloqueosEnRango(ctx, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	agenda := &domain.Agenda{Desde: desde, Hasta: hasta}
+	porDia := make(map[string]int)
+	for fecha := desde; !fecha.After(hasta); fecha = fecha.AddDate(0, 0, 1) {
+		porDia[fecha.Format(time.DateOnly)] = len(agenda.Dias)
+		abierto := domain.MinutosAbiertos(fecha, franjas[fecha.Weekday()], bloqueos)
+		agenda.Dias = append(agenda.Dias, domain.DiaAgenda{
+			Fecha:   fecha,
+			Turnos:  []*domain.Turno{},
+			Abierto: time.Duration(abierto) * time.Minute,
+		})
+	}
+	for _, t := range turnos {
+		i, ok := porDia[t.Fecha.Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+		agenda.Dias[i].Turnos = append(agenda.Dias[i].Turnos, t)
+		agenda.Dias[i].Reservado += t.Duracion
+	}
+	if agenda.Formulas, err = s.formulas(ctx, turnos); err != nil {
+		return nil, err
+	}
+	return agenda, nil
+}
+
+// formulas busca la última fórmula de cada cliente con un turno por venir
+// entre turnos, para tenerla a mano al atenderlo.
+func (s turnoService) formulas(ctx context.Context, turnos []*domain.Turno) (map[string]*domain.EntradaFicha, error) {
+	if s.fichas == nil {
+		return nil, nil
+	}
+	ahora := s.reloj()
+	var proximos []*domain.Turno
+	var clientes []string
+	for _, t := range turnos {
+		if !slices.Contains(domain.EstadosActivos, t.Estado) || !t.Inicio().After(ahora) {
+			continue
+		}
+		proximos = append(proximos, t)
+		if !slices.Contains(clientes, t.Cliente.ID) {
+			clientes = append(clientes, t.Cliente.ID)
+		}
+	}
+	if len(proximos) == 0 {
+		return nil, nil
+	}
+	porCliente, err := s.fichas.UltimasFormulas(ctx, clientes)
+	if err != nil {
+		return nil, err
+	}
+	formulas := make(map[string]*domain.EntradaFicha)
+	for _, t := range proximos {
+		if f := porCliente[t.Cliente.ID]; f != nil {
+			formulas[t.ID] = f
+		}
+	}
+	return formulas, nil
+}
@@ -0,0 +1,159 @@
+package turno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// sugerenciasPorCliente es cuántos horarios se le proponen a cada cliente
+	// afectado por un cierre.
+	sugerenciasPorCliente = 3
+	// diasParaSugerir es hasta cuántos días después del cierre se buscan horarios.
+	diasParaSugerir = 14
+)
+
+var errSinBloqueos = errors.New("bloqueos de agenda no disponibles")
+
+// CerrarAgenda bloquea enteros, de 00:00 a 24:00, los días del cierre y
+// cancela, como cancelación del negocio, todos sus turnos pendientes y
+// confirmados. Los días que ya estaban bloqueados enteros no se vuelven a
+// bloquear. A cada cliente afectado se le proponen los horarios libres más
+// cercanos después del cierre dentro de su preferencia horaria (a dos clientes
+// no se les propone el mismo) y se le deja una notificación.
+//
+// Bloqueos, cancelaciones y notificaciones se guardan en una sola transacción:
+// si algo falla no queda nada a medias. Si mientras tanto se reservó otro turno
+// en esos días, no se guarda nada y se devuelve un *domain.ConflictoTurnoError
+// con ese turno; volver a cerrar lo incluye. Los lugares cerrados no se le
+// ofrecen a la lista de espera.
+func (s turnoService) CerrarAgenda(ctx context.Context, c domain.Cierre) (*domain.InformeCierre, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if s.bloqueoService == nil {
+		return nil, errSinBloqueos
+	}
+	turnos, err := s.repo.GetEntreFechas(ctx, c.Desde, c.Hasta, domain.EstadosActivos...)
+	if err != nil {
+		return nil, err
+	}
+	existentes, err := s.bloqueoService.GetEnRango(ctx, c.Desde, c.Hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	informe := &domain.InformeCierre{Cierre: c, Bloqueos: c.Bloqueos(existentes)}
+	for _, b := range informe.Bloqueos {
+		b.ID = uuid.New().String()
+		if err := b.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	var sugeridos []*domain.Turno
+	eventos := make([]domain.EventoTurno, 0, len(turnos))
+	for _, t := range turnos {
+		evento, err := s.cancelar(t, domain.Cancelacion{Origen: domain.CanceladoPorNegocio, Motivo: c.Motivo})
+		if err != nil {
+			return nil, fmt.Errorf("cancelar turno %s: %w", t.ID, err)
+		}
+		sugerencias, err := s.sugerir(ctx, t, c, &sugeridos)
+		if err != nil {
+			return nil, err
+		}
+		eventos = append(eventos, evento)
+		informe.Afectados = append(informe.Afectados, domain.AfectadoCierre{
+			Turno:        t,
+			Notificacion: s.aviso(t, domain.MensajeCierre(t, c.Motivo, sugerencias)),
+			Sugerencias:  sugerencias,
+		})
+	}
+	if err := s.repo.CerrarAgenda(ctx, informe, eventos, s.sinOtrosTurnos(c, turnos)); err != nil {
+		return nil, err
+	}
+	return informe, nil
+}
+
+// sinOtrosTurnos arma la verificación que corre el repositorio al guardar un
+// cierre: con la agenda bloqueada, en los días cerrados no puede haber más
+// turnos por atender que los que se están cancelando.
+func (s turnoService) sinOtrosTurnos(c domain.Cierre, cancelados []*domain.Turno) func(domain.TurnosEnRango) error {
+	ids := make(map[string]bool, len(cancelados))
+	for _, t := range cancelados {
+		ids[t.ID] = true
+	}
+	return func(enRango domain.TurnosEnRango) error {
+		turnos, err := enRango(domain.InicioDelDia(c.Desde, s.zona), domain.InicioDelDia(c.Hasta.AddDate(0, 0, 1), s.zona))
+		if err != nil {
+			return err
+		}
+		var nuevos []string
+		for _, t := range turnos {
+			// el rango también trae los del día anterior que pasan la medianoche
+			if t.Fecha.Before(c.Desde) || t.Fecha.After(c.Hasta) {
+				continue
+			}
+			if !ids[t.ID] && slices.Contains(domain.EstadosActivos, t.Estado) {
+				nuevos = append(nuevos, t.ID)
+			}
+		}
+		if len(nuevos) > 0 {
+			return &domain.ConflictoTurnoError{IDs: nuevos}
+		}
+		return nil
+	}
+}
+
+// sugerir busca para t los primeros horarios libres después del cierre en la
+// franja que prefiere su cliente, salteando los que chocan con lo ya sugerido.
+// Lo que propone lo agrega a sugeridos.
+func (s turnoService) sugerir(ctx context.Context, t *domain.Turno, c domain.Cierre, sugeridos *[]*domain.Turno) ([]domain.Sugerencia, error) {
+	preferencia := t.Cliente.PreferenciaHoraria
+	dias, err := s.disponibles(ctx, domain.ConsultaDisponibilidad{
+		Desde:       c.Hasta.AddDate(0, 0, 1),
+		Hasta:       c.Hasta.AddDate(0, 0, diasParaSugerir),
+		Duracion:    t.Duracion,
+		Preferencia: &preferencia,
+	}, t.Servicios)
+	if err != nil {
+		return nil, err
+	}
+	var sugerencias []domain.Sugerencia
+	for _, d := range dias {
+		for _, h := range d.Horarios {
+			candidato := &domain.Turno{Fecha: d.Fecha, Hora: h, Duracion: t.Duracion, Servicios: t.Servicios, Zona: s.zona}
+			// chocaConAlguno no sirve acá: los sugeridos no tienen ID
+			if slices.ContainsFunc(*sugeridos, func(o *domain.Turno) bool { return candidato.ChocaCon(o, s.margen) }) {
+				continue
+			}
+			*sugeridos = append(*sugeridos, candidato)
+			sugerencias = append(sugerencias, domain.Sugerencia{Fecha: d.Fecha, Hora: h, Inicio: candidato.Inicio()})
+			if len(sugerencias) == sugerenciasPorCliente {
+				return sugerencias, nil
+			}
+		}
+	}
+	return sugerencias, nil
+}
+
+// aviso arma la notificación para el cliente de t, para guardarla junto con el
+// resto del cierre. Sin canal de notificaciones configurado devuelve nil.
+func (s turnoService) aviso(t *domain.Turno, mensaje string) *domain.Notificacion {
+	if s.notificaciones == nil {
+		return nil
+	}
+	return &domain.Notificacion{
+		ID:        uuid.New().String(),
+		ClienteID: t.Cliente.ID,
+		TurnoID:   t.ID,
+		Telefono:  t.Cliente.Telefono,
+		Mensaje:   mensaje,
+		CreadaEn:  s.reloj(),
+	}
+}
@@ -0,0 +1,152 @@
+package turno
+
+import (
+	"context"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+)
+
+// diaCompleto se usa como única franja cuando no hay horario de atención configurado.
+var diaCompleto = []domain.IntervaloHorario{{
+	Desde: domain.TimeOfDay{Hour: 0, Minute: 0},
+	Hasta: domain.TimeOfDay{Hour: 23, Minute: 59},
+}}
+
+// Disponibles devuelve, para cada día del rango consultado, los horarios de
+// inicio en los que entra un turno de la duración pedida sin pisar a otros.
+// Los horarios se ofrecen sobre una grilla (cada 15 minutos por defecto)
+// alineada con la hora en punto. Entre turnos se respeta el margen de
+// preparación y limpieza, pero no se lo controla contra el horario de atención.
+// Tampoco se ofrecen horarios que la política de reserva no permitiría sacar.
+func (s turnoService) Disponibles(ctx context.Context, c domain.ConsultaDisponibilidad) ([]domain.Disponibilidad, error) {
+	var servicios []domain.Servicio
+	if len(c.ServicioIDs) > 0 {
+		var err error
+		if servicios, err = s.buscarServicios(ctx, c.ServicioIDs); err != nil {
+			return nil, err
+		}
+		c.Duracion = domain.DuracionTotal(servicios)
+	}
+	return s.disponibles(ctx, c, servicios)
+}
+
+// disponibles hace la búsqueda de Disponibles para turnos con los servicios
+// dados, que pueden no estar ya en el catálogo.
+func (s turnoService) disponibles(ctx context.Context, c domain.ConsultaDisponibilidad, servicios []domain.Servicio) ([]domain.Disponibilidad, error) {
+	if c.Intervalo == 0 {
+		c.Intervalo = domain.IntervaloGrillaPorDefecto
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	franjas, err := s.franjasPorDia(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bloqueos, err := s.bloqueosEnRango(ctx, c.Desde, c.Hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	ahora := s.reloj()
+	var resultado []domain.Disponibilidad
+	for fecha := c.Desde; !fecha.After(c.Hasta); fecha = fecha.AddDate(0, 0, 1) {
+		horarios, err := s.horariosLibres(ctx, fecha, franjas[fecha.Weekday()], bloqueos, servicios, c, ahora)
+		if err != nil {
+			return nil, err
+		}
+		resultado = append(resultado, domain.Disponibilidad{Fecha: fecha, Horarios: horarios})
+	}
+	return resultado, nil
+}
+
+// franjasPorDia trae el horario de toda la semana de una vez para no consultarlo
+// por cada día del rango.
+func (s turnoService) franjasPorDia(ctx context.Context) (map[time.Weekday][]domain.IntervaloHorario, error) {
+	franjas := make(map[time.Weekday][]domain.IntervaloHorario, 7)
+	if s.horarioService == nil {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			franjas[d] = diaCompleto
+		}
+		return franjas, nil
+	}
+	semana, err := s.horarioService.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range semana {
+		franjas[h.Dia] = h.Intervalos
+	}
+	return franjas, nil
+}
+
+// bloqueosEnRango trae de una vez los bloqueos que pueden tocar el rango; se
+// suma un día al final por los turnos que pasan la medianoche.
+func (s turnoService) bloqueosEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Bloqueo, error) {
+	if s.bloqueoService == nil {
+		return nil, nil
+	}
+	return s.bloqueoService.GetEnRango(ctx, desde, hasta.AddDate(0, 0, 1))
+}
+
+func (s turnoService) horariosLibres(ctx context.Context, fecha time.Time, franjas []domain.IntervaloHorario, bloqueos []*domain.Bloqueo, servicios []domain.Servicio, c domain.ConsultaDisponibilidad, ahora time.Time) ([]domain.TimeOfDay, error) {
+	horarios := []domain.TimeOfDay{}
+	if len(franjas) == 0 {
+		return horarios, nil
+	}
+	// por rango y no por fecha, para ver también los turnos del día anterior
+	// que pasan la medianoche; el margen alcanza a los que terminan justo antes
+	desde := domain.InicioDelDia(fecha, s.zona)
+	hasta := domain.InicioDelDia(fecha.AddDate(0, 0, 1), s.zona)
+	ocupados, err := s.repo.GetEnRango(ctx, desde.Add(-domain.MargenMaximo), hasta.Add(domain.MargenMaximo))
+	if err != nil {
+		return nil, err
+	}
+	// los lugares ofrecidos a la lista de espera no se ofrecen a nadie más
+	ofrecidos, err := s.lugaresOfrecidos(ctx, desde.Add(-domain.MargenMaximo), hasta.Add(domain.MargenMaximo))
+	if err != nil {
+		return nil, err
+	}
+	ocupados = append(ocupados, ofrecidos...)
+
+	grilla := int(c.Intervalo / time.Minute)
+	duracion := int(c.Duracion / time.Minute)
+	for _, f := range franjas {
+		// primer múltiplo de la grilla que cae dentro de la franja
+		inicio := (f.Desde.Minutes() + grilla - 1) / grilla * grilla
+		for m := inicio; m+duracion <= f.Hasta.Minutes(); m += grilla {
+			hora := domain.TimeOfDay{Hour: m / 60, Minute: m % 60}
+			if c.Preferencia != nil && !c.Preferencia.Incluye(hora) {
+				continue
+			}
+			candidato := &domain.Turno{Fecha: fecha, Hora: hora, Duracion: c.Duracion, Servicios: servicios, Zona: s.zona}
+			if s.reserva.Permite(candidato.Inicio(), ahora) != nil {
+				continue
+			}
+			if !s.chocaConAlguno(candidato, ocupados) && !bloqueado(candidato, bloqueos) {
+				horarios = append(horarios, hora)
+			}
+		}
+	}
+	return horarios, nil
+}
+
+func bloqueado(t *domain.Turno, bloqueos []*domain.Bloqueo) bool {
+	for _, b := range bloqueos {
+		if b.SeSolapaCon(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s turnoService) chocaConAlguno(t *domain.Turno, otros []*domain.Turno) bool {
+	for _, o := range otros {
+		if o.ID != t.ID && t.ChocaCon(o, s.margen) {
+			return true
+		}
+	}
+	return false
+}
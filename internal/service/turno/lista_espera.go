@@ -0,0 +1,276 @@
+package turno
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/google/uuid"
+)
+
+var errSinListaEspera = errors.New("lista de espera no disponible")
+
+// AnotarEnEspera agrega al cliente al final de la lista de espera. Si pide
+// servicios, la duración sale de ellos.
+func (s turnoService) AnotarEnEspera(ctx context.Context, e *domain.EntradaEspera) (*domain.EntradaEspera, error) {
+	if s.espera == nil {
+		return nil, errSinListaEspera
+	}
+	if s.clienteService != nil {
+		c, err := s.clienteService.GetByID(ctx, e.Cliente.ID)
+		if err != nil {
+			return nil, err
+		}
+		e.Cliente = *c
+	}
+	// las ofertas se aceptan sin seña, así que quien la necesita no puede anotarse
+	if err := e.Cliente.PuedeReservar(0, s.regla.SenaMinima); err != nil {
+		return nil, err
+	}
+	servicios, err := s.buscarServicios(ctx, e.ServicioIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(servicios) > 0 {
+		e.Duracion = domain.DuracionTotal(servicios)
+	}
+	if e.Duracion == 0 {
+		e.Duracion = domain.DuracionPorDefecto
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	e.ID = uuid.New().String()
+	e.Estado = domain.Esperando
+	e.CreadaEn = s.reloj()
+	e.Oferta = nil
+	return s.espera.CreateOrUpdate(ctx, e)
+}
+
+func (s turnoService) GetEspera(ctx context.Context, id string) (*domain.EntradaEspera, error) {
+	if s.espera == nil {
+		return nil, errSinListaEspera
+	}
+	return s.espera.GetByID(ctx, id)
+}
+
+func (s turnoService) ListaEspera(ctx context.Context) ([]*domain.EntradaEspera, error) {
+	if s.espera == nil {
+		return nil, errSinListaEspera
+	}
+	return s.espera.GetActivas(ctx)
+}
+
+// RetirarDeEspera baja al cliente de la lista. Si tenía una oferta abierta, el
+// lugar pasa al siguiente.
+func (s turnoService) RetirarDeEspera(ctx context.Context, id string) error {
+	if s.espera == nil {
+		return errSinListaEspera
+	}
+	e, err := s.espera.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	e.Estado = domain.Retirada
+	if e.Oferta == nil {
+		_, err := s.espera.CreateOrUpdate(ctx, e)
+		return err
+	}
+	o := e.Oferta
+	o.Estado = domain.OfertaRechazada
+	if err := s.espera.GuardarOferta(ctx, o, e); err != nil {
+		return err
+	}
+	return s.ofrecerLugar(ctx, o.Lugar(s.zona), e.ID)
+}
+
+// AceptarOferta reserva el turno ofrecido. Mientras la oferta está abierta nadie
+// más puede reservar ese lugar, así que la reserva solo falla si algo cambió en
+// la agenda; en ese caso la oferta sigue pendiente hasta vencer.
+func (s turnoService) AceptarOferta(ctx context.Context, id string) (*domain.Turno, error) {
+	o, e, err := s.ofertaAbierta(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cliente := e.Cliente
+	if s.clienteService != nil {
+		c, err := s.clienteService.GetByID(ctx, e.Cliente.ID)
+		if err != nil {
+			return nil, err
+		}
+		cliente = *c
+	}
+	servicios, err := s.buscarServicios(ctx, e.ServicioIDs)
+	if err != nil {
+		return nil, err
+	}
+	nuevo := domain.NewTurno("", o.Fecha, o.Hora, e.Duracion, cliente, servicios)
+	nuevo.Zona = s.zona
+	t, err := s.crear(ctx, nuevo, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	o.Estado = domain.OfertaAceptada
+	o.TurnoID = t.ID
+	e.Estado = domain.Atendida
+	if err := s.espera.GuardarOferta(ctx, o, e); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// RechazarOferta devuelve la entrada a la lista y ofrece el lugar al siguiente.
+func (s turnoService) RechazarOferta(ctx context.Context, id string) error {
+	o, e, err := s.ofertaAbierta(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.cerrarOferta(ctx, o, e, domain.OfertaRechazada)
+}
+
+// VencerOfertas cierra las ofertas cuyo plazo terminó y pasa cada lugar al
+// siguiente de la lista. Está pensado para correr periódicamente.
+func (s turnoService) VencerOfertas(ctx context.Context) error {
+	if s.espera == nil {
+		return nil
+	}
+	vencidas, err := s.espera.GetOfertasVencidas(ctx, s.reloj())
+	if err != nil {
+		return err
+	}
+	for _, o := range vencidas {
+		e, err := s.espera.GetByID(ctx, o.EntradaID)
+		if err != nil {
+			return err
+		}
+		if err := s.cerrarOferta(ctx, o, e, domain.OfertaVencida); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ofertaAbierta carga la oferta y su entrada. Si la oferta venció y nadie la
+// cerró todavía, la cierra en el momento y devuelve domain.ErrOfertaVencida.
+func (s turnoService) ofertaAbierta(ctx context.Context, id string) (*domain.Oferta, *domain.EntradaEspera, error) {
+	if s.espera == nil {
+		return nil, nil, errSinListaEspera
+	}
+	o, err := s.espera.GetOferta(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.Estado != domain.OfertaPendiente {
+		return nil, nil, domain.ErrOfertaCerrada
+	}
+	e, err := s.espera.GetByID(ctx, o.EntradaID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.Vencida(s.reloj()) {
+		if err := s.cerrarOferta(ctx, o, e, domain.OfertaVencida); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, domain.ErrOfertaVencida
+	}
+	return o, e, nil
+}
+
+// cerrarOferta deja la oferta en el estado dado, vuelve a poner la entrada en
+// espera y ofrece el mismo lugar al siguiente.
+func (s turnoService) cerrarOferta(ctx context.Context, o *domain.Oferta, e *domain.EntradaEspera, estado domain.EstadoOferta) error {
+	o.Estado = estado
+	e.Estado = domain.Esperando
+	e.Oferta = nil
+	if err := s.espera.GuardarOferta(ctx, o, e); err != nil {
+		return err
+	}
+	return s.ofrecerLugar(ctx, o.Lugar(s.zona), e.ID)
+}
+
+// ofrecerLugar busca, por orden de llegada, la primera entrada a la que le
+// sirva el lugar que dejó libre y le hace una oferta. excluir es la entrada que
+// acaba de dejar pasar ese mismo lugar. Un lugar que otro turno ya ocupa, que
+// quedó bloqueado (como en un cierre de agenda) o que ya no se podría reservar
+// por la anticipación mínima, no se ofrece.
+func (s turnoService) ofrecerLugar(ctx context.Context, libre *domain.Turno, excluir string) error {
+	if s.espera == nil || s.reserva.Permite(libre.Inicio(), s.reloj()) != nil {
+		return nil
+	}
+	ocupado, err := s.conflictos(ctx, libre, nil)
+	if err != nil {
+		return err
+	}
+	if len(ocupado) > 0 {
+		return nil
+	}
+	if err := s.verificarBloqueos(ctx, libre); errors.Is(err, domain.ErrAgendaBloqueada) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	entradas, err := s.espera.GetEsperando(ctx, libre.Fecha)
+	if err != nil {
+		return err
+	}
+	for _, e := range entradas {
+		if e.ID == excluir || !e.Admite(libre) {
+			continue
+		}
+		if ok, err := s.puedeAceptar(ctx, e); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		ahora := s.reloj()
+		o := &domain.Oferta{
+			ID:        uuid.New().String(),
+			EntradaID: e.ID,
+			Fecha:     libre.Fecha,
+			Hora:      libre.Hora,
+			Duracion:  libre.Duracion,
+			VenceEn:   ahora.Add(s.vigenciaOferta),
+			Estado:    domain.OfertaPendiente,
+			CreadaEn:  ahora,
+		}
+		e.Estado = domain.ConOferta
+		e.Oferta = o
+		return s.espera.GuardarOferta(ctx, o, e)
+	}
+	return nil
+}
+
+// puedeAceptar indica si el cliente de la entrada puede reservar hoy sin seña:
+// la restricción pudo cambiar después de que se anotó.
+func (s turnoService) puedeAceptar(ctx context.Context, e *domain.EntradaEspera) (bool, error) {
+	cliente := e.Cliente
+	if s.clienteService != nil {
+		c, err := s.clienteService.GetByID(ctx, e.Cliente.ID)
+		if err != nil {
+			return false, err
+		}
+		cliente = *c
+	}
+	return cliente.PuedeReservar(0, s.regla.SenaMinima) == nil, nil
+}
+
+// lugaresOfrecidos devuelve como turnos los lugares con ofertas abiertas que
+// pueden tocar [desde, hasta). Se busca desde el día anterior por los lugares
+// que pasan la medianoche.
+func (s turnoService) lugaresOfrecidos(ctx context.Context, desde, hasta time.Time) ([]*domain.Turno, error) {
+	if s.espera == nil {
+		return nil, nil
+	}
+	primero, _ := domain.Separar(desde, s.zona)
+	ultimo, _ := domain.Separar(hasta, s.zona)
+	ofertas, err := s.espera.GetOfertasPendientes(ctx, primero.AddDate(0, 0, -1), ultimo, s.reloj())
+	if err != nil {
+		return nil, err
+	}
+	lugares := make([]*domain.Turno, 0, len(ofertas))
+	for _, o := range ofertas {
+		lugares = append(lugares, o.Lugar(s.zona))
+	}
+	return lugares, nil
+}
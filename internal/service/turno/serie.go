@@ -0,0 +1,215 @@
+package turno
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CrearSerie genera un turno por cada fecha de la regla a partir de t y los
+// guarda todos juntos. Si alguno cae fuera de horario o se superpone con otro
+// turno no se crea ninguno; el error de conflicto junta los IDs de todos los
+// turnos que molestan. Cada repetición tiene que caer dentro del plazo de
+// reserva y cuenta para el límite de turnos por cliente, así que una regla
+// larga no sirve para reservar más allá de la anticipación máxima.
+func (s turnoService) CrearSerie(ctx context.Context, t *domain.Turno, r domain.ReglaRecurrencia) (*domain.Serie, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if err := t.Cliente.PuedeReservar(t.Sena, s.regla.SenaMinima); err != nil {
+		return nil, err
+	}
+	fechas, err := r.Fechas(t.Fecha)
+	if err != nil {
+		return nil, err
+	}
+	serie := &domain.Serie{
+		ID:        uuid.New().String(),
+		ClienteID: t.Cliente.ID,
+		Regla:     r,
+		Inicio:    t.Fecha,
+	}
+	for i, f := range fechas {
+		o := *t
+		o.ID = uuid.New().String()
+		o.Fecha = f
+		o.Estado = domain.Pendiente
+		o.SerieID = serie.ID
+		if i > 0 {
+			o.Sena = 0 // la seña se deja una sola vez, en el primer turno
+		}
+		serie.Turnos = append(serie.Turnos, &o)
+	}
+	if err := s.verificarPlazos(serie.Turnos...); err != nil {
+		return nil, err
+	}
+	if err := s.verificarLimite(ctx, serie.Turnos...); err != nil {
+		return nil, err
+	}
+	if err := s.verificarVarios(ctx, serie.Turnos); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CrearSerie(ctx, serie, s.sinConflictos(serie.Turnos...)); err != nil {
+		return nil, err
+	}
+	return serie, nil
+}
+
+func (s turnoService) GetSerie(ctx context.Context, id string) (*domain.Serie, error) {
+	if id == "" {
+		return nil, errors.New("ID requerido para obtener serie")
+	}
+	return s.repo.GetSerie(ctx, id)
+}
+
+// ActualizarSerie aplica la edición de t a los turnos de su serie que entren en
+// el alcance. Todos toman la hora, duración y servicios de t, y se corren de
+// fecha tantos días como se haya movido t. El cliente no se puede cambiar. Los
+// turnos ya cerrados
+// (cancelados, completados, ausentes) no se tocan. Como en Update, el plazo y el
+// límite de turnos por cliente se controlan solo si t cambia de horario.
+func (s turnoService) ActualizarSerie(ctx context.Context, t *domain.Turno, alcance domain.AlcanceSerie) ([]*domain.Turno, error) {
+	if alcance == domain.SoloEste {
+		res, err := s.Update(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.Turno{res}, nil
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	elegido, serie, err := s.turnoYSerie(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Cliente.ID != serie.ClienteID {
+		return nil, domain.ErrClienteDeSerie
+	}
+	dias := int(t.Fecha.Sub(elegido.Fecha).Hours() / 24)
+	movido := !t.Inicio().Equal(elegido.Inicio())
+
+	var editados []*domain.Turno
+	for _, o := range serie.Turnos {
+		if o.Estado.EsFinal() || !alcance.Incluye(elegido, o) {
+			continue
+		}
+		e := *o
+		e.Fecha = o.Fecha.AddDate(0, 0, dias)
+		e.Hora = t.Hora
+		e.Duracion = t.Duracion
+		e.Servicios = t.Servicios
+		e.Cliente = t.Cliente
+		if o.ID == elegido.ID {
+			e.Sena = t.Sena
+		}
+		editados = append(editados, &e)
+	}
+	if len(editados) == 0 {
+		return nil, fmt.Errorf("%w: no quedan turnos abiertos en la serie", domain.ErrTransicionInvalida)
+	}
+	if movido {
+		if err := s.verificarPlazos(editados...); err != nil {
+			return nil, err
+		}
+		if err := s.verificarLimite(ctx, editados...); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.verificarVarios(ctx, editados); err != nil {
+		return nil, err
+	}
+	if err := s.repo.GuardarVarios(ctx, editados, s.sinConflictos(editados...)); err != nil {
+		return nil, err
+	}
+	return editados, nil
+}
+
+// CancelarSerie cancela, con la misma política que Cancelar, los turnos
+// abiertos de la serie que entren en el alcance. Se guardan todos juntos: si
+// alguno no se puede cancelar no se cancela ninguno.
+func (s turnoService) CancelarSerie(ctx context.Context, id string, alcance domain.AlcanceSerie, c domain.Cancelacion) ([]*domain.Turno, error) {
+	if alcance == domain.SoloEste {
+		res, err := s.Cancelar(ctx, id, c)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.Turno{res}, nil
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	elegido, serie, err := s.turnoYSerie(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var cancelados []*domain.Turno
+	var eventos []domain.EventoTurno
+	tardia := false
+	for _, o := range serie.Turnos {
+		if o.Estado.EsFinal() || !alcance.Incluye(elegido, o) {
+			continue
+		}
+		evento, err := s.cancelar(o, c)
+		if err != nil {
+			return nil, fmt.Errorf("turno del %s: %w", o.Fecha.Format(time.DateOnly), err)
+		}
+		tardia = tardia || o.Cancelacion.Tardia
+		cancelados = append(cancelados, o)
+		eventos = append(eventos, evento)
+	}
+	if len(cancelados) == 0 {
+		return nil, nil
+	}
+	if err := s.repo.CambiarEstados(ctx, cancelados, eventos); err != nil {
+		return nil, err
+	}
+	if tardia {
+		if err := s.aplicarReglaAusencias(ctx, serie.ClienteID); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range cancelados {
+		if err := s.ofrecerLugar(ctx, o, ""); err != nil {
+			return nil, err
+		}
+	}
+	return cancelados, nil
+}
+
+func (s turnoService) turnoYSerie(ctx context.Context, id string) (*domain.Turno, *domain.Serie, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.SerieID == "" {
+		return nil, nil, errors.New("el turno no pertenece a una serie")
+	}
+	serie, err := s.repo.GetSerie(ctx, t.SerieID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, serie, nil
+}
+
+// verificarVarios controla el horario, los bloqueos y los lugares ofrecidos a
+// la lista de espera de un grupo de turnos que se guardan juntos. La
+// superposición la controla el repositorio al guardarlos.
+func (s turnoService) verificarVarios(ctx context.Context, turnos []*domain.Turno) error {
+	for _, t := range turnos {
+		if err := s.verificarHorario(ctx, t); err != nil {
+			return fmt.Errorf("turno del %s: %w", t.Fecha.Format(time.DateOnly), err)
+		}
+		if err := s.verificarBloqueos(ctx, t); err != nil {
+			return fmt.Errorf("turno del %s: %w", t.Fecha.Format(time.DateOnly), err)
+		}
+		if err := s.verificarOfertas(ctx, "", t); err != nil {
+			return fmt.Errorf("turno del %s: %w", t.Fecha.Format(time.DateOnly), err)
+		}
+	}
+	return nil
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/bloqueo"
 	service "github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/cliente"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/horario"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/servicio"
 	"github.com/google/uuid"
 )
 
@@ -17,31 +21,215 @@ type TurnoService interface {
 	Create(ctx context.Context, t *domain.Turno) (*domain.Turno, error)
 	Update(ctx context.Context, t *domain.Turno) (*domain.Turno, error)
 	Delete(ctx context.Context, id string) error
-	GetByFecha(ctx context.Context, fecha time.Time) ([]*domain.Turno, error)
-	GetAll(ctx context.Context) ([]*domain.Turno, error)
+	GetByID(ctx context.Context, id string) (*domain.Turno, error)
+	GetByFecha(ctx context.Context, fecha time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error)
+	GetAll(ctx context.Context, estados ...domain.EstadoTurno) ([]*domain.Turno, error)
+	Buscar(ctx context.Context, f domain.FiltroTurnos) ([]*domain.Turno, error)
+	HistorialCliente(ctx context.Context, clienteID string) (*domain.HistorialCliente, error)
 	ToDomain(ctx context.Context, t *dto.TurnoRequest) (*domain.Turno, error)
+	Disponibles(ctx context.Context, c domain.ConsultaDisponibilidad) ([]domain.Disponibilidad, error)
+	Agenda(ctx context.Context, desde, hasta time.Time) (*domain.Agenda, error)
+	Confirmar(ctx context.Context, id string) (*domain.Turno, error)
+	Cancelar(ctx context.Context, id string, c domain.Cancelacion) (*domain.Turno, error)
+	Completar(ctx context.Context, id string) (*domain.Turno, error)
+	MarcarAusente(ctx context.Context, id string) (*domain.Turno, error)
+	Historial(ctx context.Context, id string) ([]domain.EventoTurno, error)
+	Reprogramar(ctx context.Context, id string, fecha time.Time, hora domain.TimeOfDay) (*domain.Turno, error)
+	CrearSerie(ctx context.Context, t *domain.Turno, r domain.ReglaRecurrencia) (*domain.Serie, error)
+	GetSerie(ctx context.Context, id string) (*domain.Serie, error)
+	ActualizarSerie(ctx context.Context, t *domain.Turno, alcance domain.AlcanceSerie) ([]*domain.Turno, error)
+	CancelarSerie(ctx context.Context, id string, alcance domain.AlcanceSerie, c domain.Cancelacion) ([]*domain.Turno, error)
+	AnotarEnEspera(ctx context.Context, e *domain.EntradaEspera) (*domain.EntradaEspera, error)
+	GetEspera(ctx context.Context, id string) (*domain.EntradaEspera, error)
+	ListaEspera(ctx context.Context) ([]*domain.EntradaEspera, error)
+	RetirarDeEspera(ctx context.Context, id string) error
+	AceptarOferta(ctx context.Context, id string) (*domain.Turno, error)
+	RechazarOferta(ctx context.Context, id string) error
+	VencerOfertas(ctx context.Context) error
+	CerrarAgenda(ctx context.Context, c domain.Cierre) (*domain.InformeCierre, error)
 }
 
 type turnoService struct {
-	repo           repository.TurnoRepository
-	clienteService service.ClienteService
+	repo            repository.TurnoRepository
+	clienteService  service.ClienteService
+	servicioService servicio.ServicioService
+	horarioService  horario.HorarioService
+	bloqueoService  bloqueo.BloqueoService
+	margen          domain.Margen
+	reloj           func() time.Time
+	zona            *time.Location
+	politica        domain.PoliticaCancelacion
+	reserva         domain.PoliticaReserva
+	limite          domain.LimiteReservas
+	regla           domain.ReglaAusencias
+	espera          repository.ListaEsperaRepository
+	vigenciaOferta  time.Duration
+	notificaciones  repository.NotificacionRepository
+	fichas          repository.FichaRepository
 }
 
-func NewTurnoService(repo repository.TurnoRepository, cs service.ClienteService) *turnoService {
-	return &turnoService{
+// Option configura dependencias opcionales de turnoService.
+type Option func(*turnoService)
+
+// WithServicioService habilita reservar turnos indicando servicios del catálogo.
+func WithServicioService(ss servicio.ServicioService) Option {
+	return func(s *turnoService) {
+		s.servicioService = ss
+	}
+}
+
+// WithHorarioService hace que solo se acepten turnos dentro del horario de atención.
+func WithHorarioService(hs horario.HorarioService) Option {
+	return func(s *turnoService) {
+		s.horarioService = hs
+	}
+}
+
+// WithBloqueoService hace que los bloqueos de agenda cuenten como horario no disponible.
+func WithBloqueoService(bs bloqueo.BloqueoService) Option {
+	return func(s *turnoService) {
+		s.bloqueoService = bs
+	}
+}
+
+// WithMargen fija la preparación y limpieza que se dejan alrededor de cada turno
+// cuyos servicios no definen las suyas.
+func WithMargen(m domain.Margen) Option {
+	return func(s *turnoService) {
+		s.margen = m
+	}
+}
+
+// WithReloj reemplaza time.Now como fuente de la hora actual.
+func WithReloj(reloj func() time.Time) Option {
+	return func(s *turnoService) {
+		s.reloj = reloj
+	}
+}
+
+// WithZonaHoraria fija la zona en la que atiende el negocio: en ella se leen las
+// horas de los turnos y se decide qué día es hoy. Sin esta opción se usa UTC.
+func WithZonaHoraria(zona *time.Location) Option {
+	return func(s *turnoService) {
+		s.zona = zona
+	}
+}
+
+// WithPoliticaCancelacion fija la anticipación mínima que se exige al cliente para cancelar.
+func WithPoliticaCancelacion(p domain.PoliticaCancelacion) Option {
+	return func(s *turnoService) {
+		s.politica = p
+	}
+}
+
+// WithPoliticaReserva fija con cuánta anticipación mínima y máxima se pueden
+// sacar turnos. Sin esta opción solo se rechazan los turnos que ya pasaron.
+func WithPoliticaReserva(p domain.PoliticaReserva) Option {
+	return func(s *turnoService) {
+		s.reserva = p
+	}
+}
+
+// WithLimiteReservas acota cuántos turnos por venir puede tener cada cliente.
+func WithLimiteReservas(l domain.LimiteReservas) Option {
+	return func(s *turnoService) {
+		s.limite = l
+	}
+}
+
+// WithReglaAusencias restringe automáticamente a los clientes que acumulan ausencias.
+func WithReglaAusencias(r domain.ReglaAusencias) Option {
+	return func(s *turnoService) {
+		s.regla = r
+	}
+}
+
+// WithListaEspera hace que cada lugar que se libera se le ofrezca a la lista de
+// espera. La oferta queda abierta durante vigencia.
+func WithListaEspera(repo repository.ListaEsperaRepository, vigencia time.Duration) Option {
+	return func(s *turnoService) {
+		s.espera = repo
+		s.vigenciaOferta = vigencia
+	}
+}
+
+// WithNotificaciones guarda los avisos a clientes que generan las operaciones
+// del servicio. Sin esta opción no se avisa a nadie. El cierre de agenda guarda
+// sus avisos en su propia transacción, junto con las cancelaciones.
+func WithNotificaciones(repo repository.NotificacionRepository) Option {
+	return func(s *turnoService) {
+		s.notificaciones = repo
+	}
+}
+
+// WithFichas muestra en la agenda la última fórmula de la ficha técnica de
+// cada cliente con turno por venir.
+func WithFichas(repo repository.FichaRepository) Option {
+	return func(s *turnoService) {
+		s.fichas = repo
+	}
+}
+
+type claveExcederLimite struct{}
+
+// PermitirExcederLimite devuelve un ctx con el que las reservas no controlan el
+// límite de turnos por cliente. Es para que el administrador pueda dar un turno
+// de más a propósito.
+func PermitirExcederLimite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, claveExcederLimite{}, true)
+}
+
+func puedeExcederLimite(ctx context.Context) bool {
+	permitido, _ := ctx.Value(claveExcederLimite{}).(bool)
+	return permitido
+}
+
+func NewTurnoService(repo repository.TurnoRepository, cs service.ClienteService, opts ...Option) *turnoService {
+	s := &turnoService{
 		repo:           repo,
 		clienteService: cs,
+		reloj:          time.Now,
+		zona:           time.UTC,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s turnoService) Create(ctx context.Context, t *domain.Turno) (*domain.Turno, error) {
+	return s.crear(ctx, t, "")
+}
+
+// crear hace el trabajo de Create. oferta es la oferta de la lista de espera que
+// se está aceptando: su lugar no cuenta como ocupado para este turno.
+func (s turnoService) crear(ctx context.Context, t *domain.Turno, oferta string) (*domain.Turno, error) {
 	if err := t.Validate(); err != nil {
 		return nil, err
 	}
 	if t.ID == "" {
 		t.ID = uuid.New().String()
 	}
-	return s.repo.CreateOrUpdate(ctx, t)
+	t.Estado = domain.Pendiente
+	if err := t.Cliente.PuedeReservar(t.Sena, s.regla.SenaMinima); err != nil {
+		return nil, err
+	}
+	if err := s.verificarPlazo(t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarLimite(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarHorario(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarBloqueos(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarOfertas(ctx, oferta, t); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrUpdate(ctx, t, s.sinConflictos(t))
 }
 
 func (s turnoService) Update(ctx context.Context, t *domain.Turno) (*domain.Turno, error) {
@@ -51,19 +239,412 @@ func (s turnoService) Update(ctx context.Context, t *domain.Turno) (*domain.Turn
 	if t.ID == "" {
 		return nil, errors.New("ID requerido para actualizar")
 	}
-	return s.repo.CreateOrUpdate(ctx, t)
+	actual, err := s.repo.GetByID(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if actual.Estado.EsFinal() {
+		return nil, fmt.Errorf("%w: el turno está %s", domain.ErrTransicionInvalida, actual.Estado)
+	}
+	if actual.SerieID != "" && t.Cliente.ID != actual.Cliente.ID {
+		return nil, domain.ErrClienteDeSerie
+	}
+	// el estado no se edita por acá, solo con las acciones confirmar, cancelar, etc.
+	t.Estado = actual.Estado
+	t.SerieID = actual.SerieID
+	// si no se mueve de horario se puede editar aunque ya esté cerca o haya empezado
+	if !t.Inicio().Equal(actual.Inicio()) {
+		if err := s.verificarPlazo(t); err != nil {
+			return nil, err
+		}
+		if err := s.verificarLimite(ctx, t); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.verificarHorario(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarBloqueos(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarOfertas(ctx, "", t); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateOrUpdate(ctx, t, s.sinConflictos(t))
+}
+
+// verificarPlazo controla que t se reserve con la anticipación que pide la política.
+func (s turnoService) verificarPlazo(t *domain.Turno) error {
+	return s.reserva.Permite(t.Inicio(), s.reloj())
+}
+
+// verificarPlazos controla el plazo de reserva de cada uno de los turnos que
+// se guardan juntos.
+func (s turnoService) verificarPlazos(turnos ...*domain.Turno) error {
+	for _, t := range turnos {
+		if err := s.verificarPlazo(t); err != nil {
+			if len(turnos) > 1 {
+				return fmt.Errorf("turno del %s: %w", t.Fecha.Format(time.DateOnly), err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// verificarLimite controla que el cliente no supere el límite de turnos por
+// venir al reservar turnos, todos del mismo cliente, salvo que ctx lo permita.
+// Se suman de a uno, así que en una serie cada repetición cuenta para las
+// siguientes.
+func (s turnoService) verificarLimite(ctx context.Context, turnos ...*domain.Turno) error {
+	if !s.limite.Activo() || puedeExcederLimite(ctx) || len(turnos) == 0 {
+		return nil
+	}
+	ahora := s.reloj()
+	hoy, _ := domain.Separar(ahora, s.zona)
+	existentes, err := s.repo.Buscar(ctx, domain.FiltroTurnos{
+		Desde:     hoy,
+		ClienteID: turnos[0].Cliente.ID,
+		Estados:   domain.EstadosActivos,
+	})
+	if err != nil {
+		return err
+	}
+	var activos []*domain.Turno
+	for _, o := range existentes {
+		if o.Inicio().After(ahora) {
+			activos = append(activos, o)
+		}
+	}
+	for _, t := range turnos {
+		if err := s.limite.Permite(t, activos); err != nil {
+			if len(turnos) > 1 {
+				return fmt.Errorf("turno del %s: %w", t.Fecha.Format(time.DateOnly), err)
+			}
+			return err
+		}
+		// si t ya estaba (porque se mueve), reemplaza a su versión anterior
+		activos = slices.DeleteFunc(activos, func(o *domain.Turno) bool { return o.ID == t.ID })
+		activos = append(activos, t)
+	}
+	return nil
+}
+
+// verificarHorario devuelve domain.ErrFueraDeHorario si t no entra en el horario
+// de atención de su día. Sin horarioService configurado no se restringe nada.
+func (s turnoService) verificarHorario(ctx context.Context, t *domain.Turno) error {
+	if s.horarioService == nil {
+		return nil
+	}
+	cubre, err := s.horarioService.Cubre(ctx, t)
+	if err != nil {
+		return err
+	}
+	if !cubre {
+		return domain.ErrFueraDeHorario
+	}
+	return nil
+}
+
+// verificarBloqueos devuelve un *domain.BloqueoError si t cae sobre un bloqueo de la agenda.
+func (s turnoService) verificarBloqueos(ctx context.Context, t *domain.Turno) error {
+	if s.bloqueoService == nil {
+		return nil
+	}
+	b, err := s.bloqueoService.Bloqueante(ctx, t)
+	if err != nil {
+		return err
+	}
+	if b != nil {
+		return &domain.BloqueoError{Motivo: b.Motivo}
+	}
+	return nil
+}
+
+// verificarOfertas controla que los turnos no pisen un lugar ofrecido a la
+// lista de espera: mientras la oferta está abierta, el lugar es de esa entrada.
+// ignorar es la oferta que se está aceptando, si hay una.
+func (s turnoService) verificarOfertas(ctx context.Context, ignorar string, turnos ...*domain.Turno) error {
+	for _, t := range turnos {
+		desde, hasta := s.ventana(t)
+		lugares, err := s.lugaresOfrecidos(ctx, desde, hasta)
+		if err != nil {
+			return err
+		}
+		for _, l := range lugares {
+			if l.ID != ignorar && t.ChocaCon(l, s.margen) {
+				return domain.ErrLugarOfrecido
+			}
+		}
+	}
+	return nil
+}
+
+// sinConflictos arma la verificación que el repositorio corre con la agenda
+// bloqueada, dentro de la transacción que guarda los turnos: si alguno choca con
+// un turno guardado devuelve un *domain.ConflictoTurnoError con los IDs de todos
+// los que molestan. Los turnos que se guardan juntos no cuentan como conflicto
+// entre sí, y cada uno se ignora a sí mismo para que Update no choque consigo.
+func (s turnoService) sinConflictos(turnos ...*domain.Turno) func(domain.TurnosEnRango) error {
+	grupo := make(map[string]bool, len(turnos))
+	for _, t := range turnos {
+		grupo[t.ID] = true
+	}
+	return func(enRango domain.TurnosEnRango) error {
+		var ids []string
+		for _, t := range turnos {
+			existentes, err := enRango(s.ventana(t))
+			if err != nil {
+				return err
+			}
+			ids = append(ids, s.chocan(t, existentes, grupo)...)
+		}
+		if len(ids) > 0 {
+			return &domain.ConflictoTurnoError{IDs: ids}
+		}
+		return nil
+	}
+}
+
+// conflictos devuelve los IDs de los turnos guardados que chocan con t, margen
+// incluido, salvo t mismo y los que estén en ignorar.
+func (s turnoService) conflictos(ctx context.Context, t *domain.Turno, ignorar map[string]bool) ([]string, error) {
+	desde, hasta := s.ventana(t)
+	existentes, err := s.repo.GetEnRango(ctx, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	return s.chocan(t, existentes, ignorar), nil
+}
+
+// ventana es el rango en que hay que buscar turnos que puedan chocar con t: lo
+// que ocupa t ampliado en domain.MargenMaximo, para alcanzar a los turnos cuyo
+// margen llega hasta él.
+func (s turnoService) ventana(t *domain.Turno) (desde, hasta time.Time) {
+	desde, hasta = t.Ocupa(s.margen)
+	return desde.Add(-domain.MargenMaximo), hasta.Add(domain.MargenMaximo)
+}
+
+func (s turnoService) chocan(t *domain.Turno, existentes []*domain.Turno, ignorar map[string]bool) []string {
+	var ids []string
+	for _, e := range existentes {
+		if e.ID != t.ID && !ignorar[e.ID] && t.ChocaCon(e, s.margen) {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids
 }
 
 func (s turnoService) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	if s.espera == nil {
+		return s.repo.Delete(ctx, id)
+	}
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if t.Estado == domain.Cancelado {
+		return nil // su lugar ya se ofreció al cancelarlo
+	}
+	return s.ofrecerLugar(ctx, t, "")
+}
+
+func (s turnoService) GetByID(ctx context.Context, id string) (*domain.Turno, error) {
+	if id == "" {
+		return nil, errors.New("ID requerido para obtener turno")
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s turnoService) GetByFecha(ctx context.Context, fecha time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
+	return s.repo.GetByFecha(ctx, fecha, estados...)
+}
+
+func (s turnoService) GetAll(ctx context.Context, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
+	return s.repo.GetAll(ctx, estados...)
+}
+
+func (s turnoService) Buscar(ctx context.Context, f domain.FiltroTurnos) ([]*domain.Turno, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Buscar(ctx, f)
+}
+
+// HistorialCliente devuelve todos los turnos del cliente, incluidos los
+// cancelados, con el resumen de sus visitas.
+func (s turnoService) HistorialCliente(ctx context.Context, clienteID string) (*domain.HistorialCliente, error) {
+	if clienteID == "" {
+		return nil, errors.New("ID de cliente requerido")
+	}
+	turnos, err := s.repo.Buscar(ctx, domain.FiltroTurnos{ClienteID: clienteID})
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewHistorialCliente(clienteID, turnos), nil
+}
+
+func (s turnoService) Confirmar(ctx context.Context, id string) (*domain.Turno, error) {
+	return s.cambiarEstado(ctx, id, domain.Confirmado, "")
+}
+
+// Cancelar aplica la política de cancelación: si el cliente cancela con menos
+// anticipación de la pedida, se rechaza o queda marcada como tardía según la
+// configuración. Todo camino que cancele turnos tiene que pasar por acá o, si
+// guarda la cancelación junto con otros cambios, por cancelar.
+func (s turnoService) Cancelar(ctx context.Context, id string, c domain.Cancelacion) (*domain.Turno, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	evento, err := s.cancelar(t, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CambiarEstado(ctx, t, evento); err != nil {
+		return nil, err
+	}
+	if t.Cancelacion.Tardia {
+		if err := s.aplicarReglaAusencias(ctx, t.Cliente.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.ofrecerLugar(ctx, t, ""); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// cancelar le aplica a t la política de cancelación y lo pasa a cancelado, sin
+// guardar nada. Devuelve el evento para el historial.
+func (s turnoService) cancelar(t *domain.Turno, c domain.Cancelacion) (domain.EventoTurno, error) {
+	ahora := s.reloj()
+	c.Fecha = ahora
+	c.Tardia = false
+	if c.Origen == domain.CanceladoPorCliente && s.politica.EsTardia(t.Inicio(), ahora) {
+		if s.politica.RechazarTardias {
+			return domain.EventoTurno{}, fmt.Errorf("%w: se requieren %s de aviso", domain.ErrCancelacionTardia, s.politica.AnticipacionMinima)
+		}
+		c.Tardia = true
+	}
+	evento, err := t.CambiarEstado(domain.Cancelado, ahora, c.Detalle())
+	if err != nil {
+		return domain.EventoTurno{}, err
+	}
+	t.Cancelacion = &c
+	return evento, nil
+}
+
+func (s turnoService) Completar(ctx context.Context, id string) (*domain.Turno, error) {
+	return s.cambiarEstado(ctx, id, domain.Completado, "")
+}
+
+func (s turnoService) MarcarAusente(ctx context.Context, id string) (*domain.Turno, error) {
+	t, err := s.cambiarEstado(ctx, id, domain.Ausente, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.aplicarReglaAusencias(ctx, t.Cliente.ID); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// aplicarReglaAusencias cuenta los incumplimientos del cliente dentro de la
+// ventana de la regla, sin ir más atrás de la última vez que se le levantó una
+// restricción, y lo restringe si llegó al máximo.
+func (s turnoService) aplicarReglaAusencias(ctx context.Context, clienteID string) error {
+	if !s.regla.Activa() || s.clienteService == nil {
+		return nil
+	}
+	c, err := s.clienteService.GetByID(ctx, clienteID)
+	if err != nil {
+		return err
+	}
+	if c.Restriccion >= s.regla.Accion {
+		return nil
+	}
+	desde := s.reloj().Add(-s.regla.Ventana)
+	if c.RestriccionLevantada.After(desde) {
+		desde = c.RestriccionLevantada
+	}
+	ausencias, tardias, err := s.repo.ContarIncumplimientos(ctx, clienteID, desde)
+	if err != nil {
+		return err
+	}
+	if !s.regla.Alcanzada(ausencias, tardias) {
+		return nil
+	}
+	_, err = s.clienteService.Restringir(ctx, clienteID, s.regla.Accion)
+	return err
+}
+
+// Reprogramar mueve el turno a otra fecha y hora. El horario y los bloqueos se
+// controlan antes; la superposición, dentro de la misma transacción que guarda
+// el cambio, así que si otro tomó el lugar mientras tanto devuelve un
+// *domain.ConflictoTurnoError. El horario anterior queda en el historial, y la
+// parte que el turno ya no ocupa se le ofrece a la lista de espera.
+func (s turnoService) Reprogramar(ctx context.Context, id string, fecha time.Time, hora domain.TimeOfDay) (*domain.Turno, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	anterior := *t
+	evento, err := t.Reprogramar(fecha, hora, s.reloj())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verificarPlazo(t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarLimite(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarHorario(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarBloqueos(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := s.verificarOfertas(ctx, "", t); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Reprogramar(ctx, t, evento, s.sinConflictos(t)); err != nil {
+		return nil, err
+	}
+	// si el turno se corrió un poco, solo se ofrece lo que dejó de ocupar
+	for _, libre := range anterior.Liberado(t) {
+		if err := s.ofrecerLugar(ctx, libre, ""); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
 }
 
-func (s turnoService) GetByFecha(ctx context.Context, fecha time.Time) ([]*domain.Turno, error) {
-	return s.repo.GetByFecha(ctx, fecha)
+func (s turnoService) Historial(ctx context.Context, id string) ([]domain.EventoTurno, error) {
+	return s.repo.GetHistorial(ctx, id)
 }
 
-func (s turnoService) GetAll(ctx context.Context) ([]*domain.Turno, error) {
-	return s.repo.GetAll(ctx)
+// cambiarEstado carga el turno, valida la transición y la guarda junto con su evento.
+func (s turnoService) cambiarEstado(ctx context.Context, id string, nuevo domain.EstadoTurno, detalle string) (*domain.Turno, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	evento, err := t.CambiarEstado(nuevo, s.reloj(), detalle)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CambiarEstado(ctx, t, evento); err != nil {
+		return nil, err
+	}
+	return t, nil
 }
 
 // este no recibe test
@@ -76,15 +657,58 @@ func (s turnoService) ToDomain(ctx context.Context, t *dto.TurnoRequest) (*domai
 	if err != nil {
 		return nil, fmt.Errorf("error de parse timeofday: %w", err)
 	}
+	servicios, err := s.buscarServicios(ctx, t.ServicioIDs)
+	if err != nil {
+		return nil, err
+	}
+	// con servicios la duración sale de lo reservado; sin ellos se respeta la pedida
+	duracion := domain.DuracionTotal(servicios)
+	if duracion == 0 {
+		duracion = time.Duration(t.Duracion) * time.Minute
+	}
+	if duracion == 0 {
+		duracion = domain.DuracionPorDefecto
+	}
 	cliente, _ := s.clienteService.GetByID(ctx, t.ClienteID)
 	if cliente == nil {
 		return nil, fmt.Errorf("cliente vacio")
 	}
-	return domain.NewTurno(
+	turno := domain.NewTurno(
 		t.ID,
 		fecha,
 		hora,
+		duracion,
 		*cliente,
-	), nil
+		servicios,
+	)
+	turno.Sena = t.Sena
+	turno.Zona = s.zona
+	return turno, nil
 
 }
+
+func (s turnoService) buscarServicios(ctx context.Context, ids []string) ([]domain.Servicio, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	for i, id := range ids {
+		if slices.Contains(ids[:i], id) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrServicioRepetido, id)
+		}
+	}
+	if s.servicioService == nil {
+		return nil, errors.New("catálogo de servicios no disponible")
+	}
+	servicios := make([]domain.Servicio, 0, len(ids))
+	for _, id := range ids {
+		sv, err := s.servicioService.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("servicio %s no encontrado: %w", id, err)
+		}
+		if !sv.Activo {
+			return nil, fmt.Errorf("el servicio %s no está activo", sv.Nombre)
+		}
+		servicios = append(servicios, *sv)
+	}
+	return servicios, nil
+}
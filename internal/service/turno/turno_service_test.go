@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/domain"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/dto"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/repository"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/bloqueo"
+	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/cliente"
 	"github.com/IvanMiranda1/gestion-de-turnos-peluqueria-unipersonal/internal/service/turno"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +23,21 @@ type MockTurnoRepository struct {
 	mock.Mock
 }
 
-func (m *MockTurnoRepository) CreateOrUpdate(ctx context.Context, t *domain.Turno) (*domain.Turno, error) {
+// Los métodos que reciben verificar lo corren con GetEnRango antes de registrar
+// la llamada, como el repositorio real dentro de su transacción.
+func (m *MockTurnoRepository) verificar(ctx context.Context, verificar func(domain.TurnosEnRango) error) error {
+	if verificar == nil {
+		return nil
+	}
+	return verificar(func(desde, hasta time.Time) ([]*domain.Turno, error) {
+		return m.GetEnRango(ctx, desde, hasta)
+	})
+}
+
+func (m *MockTurnoRepository) CreateOrUpdate(ctx context.Context, t *domain.Turno, verificar func(domain.TurnosEnRango) error) (*domain.Turno, error) {
+	if err := m.verificar(ctx, verificar); err != nil {
+		return nil, err
+	}
 	args := m.Called(ctx, t)
 	if args.Get(0) != nil {
 		return args.Get(0).(*domain.Turno), args.Error(1)
@@ -26,87 +45,1218 @@ func (m *MockTurnoRepository) CreateOrUpdate(ctx context.Context, t *domain.Turn
 	return nil, args.Error(1)
 }
 
+func (m *MockTurnoRepository) CerrarAgenda(ctx context.Context, informe *domain.InformeCierre, eventos []domain.EventoTurno, verificar func(domain.TurnosEnRango) error) error {
+	if err := m.verificar(ctx, verificar); err != nil {
+		return err
+	}
+	args := m.Called(ctx, informe, eventos)
+	return args.Error(0)
+}
+
 func (m *MockTurnoRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockTurnoRepository) GetByFecha(ctx context.Context, fecha time.Time) ([]*domain.Turno, error) {
-	args := m.Called(ctx, fecha)
+func (m *MockTurnoRepository) GetByID(ctx context.Context, id string) (*domain.Turno, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Turno), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTurnoRepository) GetByFecha(ctx context.Context, fecha time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
+	args := m.Called(ctx, fecha, estados)
 	if args.Get(0) != nil {
 		return args.Get(0).([]*domain.Turno), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockTurnoRepository) GetAll(ctx context.Context) ([]*domain.Turno, error) {
-	args := m.Called(ctx)
+func (m *MockTurnoRepository) GetAll(ctx context.Context, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
+	args := m.Called(ctx, estados)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Turno), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTurnoRepository) GetEntreFechas(ctx context.Context, desde, hasta time.Time, estados ...domain.EstadoTurno) ([]*domain.Turno, error) {
+	args := m.Called(ctx, desde, hasta, estados)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Turno), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTurnoRepository) Buscar(ctx context.Context, f domain.FiltroTurnos) ([]*domain.Turno, error) {
+	args := m.Called(ctx, f)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Turno), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTurnoRepository) GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Turno, error) {
+	args := m.Called(ctx, desde, hasta)
 	if args.Get(0) != nil {
 		return args.Get(0).([]*domain.Turno), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func TestTurnoService_Create(t *testing.T) {
-	t.Run("Create Return Error Validate()", func(t *testing.T) {
-		s := turno.NewTurnoService(nil, nil)
-		res, err := s.Create(context.Background(), &domain.Turno{
-			ID:    "",
-			Fecha: time.Time{},
-			Hora:  domain.TimeOfDay{},
-		})
-		assert.Error(t, err)
+func (m *MockTurnoRepository) CambiarEstado(ctx context.Context, t *domain.Turno, e domain.EventoTurno) error {
+	args := m.Called(ctx, t, e)
+	return args.Error(0)
+}
+
+func (m *MockTurnoRepository) CambiarEstados(ctx context.Context, turnos []*domain.Turno, eventos []domain.EventoTurno) error {
+	args := m.Called(ctx, turnos, eventos)
+	return args.Error(0)
+}
+
+func (m *MockTurnoRepository) GetHistorial(ctx context.Context, turnoID string) ([]domain.EventoTurno, error) {
+	args := m.Called(ctx, turnoID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]domain.EventoTurno), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// Reprogramar le pasa a verificar los turnos del primer valor de retorno, como
+// hace la transacción real con los que lee de la base.
+func (m *MockTurnoRepository) Reprogramar(ctx context.Context, t *domain.Turno, e domain.EventoTurno, verificar func(domain.TurnosEnRango) error) error {
+	if err := m.verificar(ctx, verificar); err != nil {
+		return err
+	}
+	args := m.Called(ctx, t, e)
+	return args.Error(0)
+}
+
+func (m *MockTurnoRepository) ContarIncumplimientos(ctx context.Context, clienteID string, desde time.Time) (int, int, error) {
+	args := m.Called(ctx, clienteID, desde)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockTurnoRepository) GuardarVarios(ctx context.Context, turnos []*domain.Turno, verificar func(domain.TurnosEnRango) error) error {
+	if err := m.verificar(ctx, verificar); err != nil {
+		return err
+	}
+	args := m.Called(ctx, turnos)
+	return args.Error(0)
+}
+
+func (m *MockTurnoRepository) CrearSerie(ctx context.Context, s *domain.Serie, verificar func(domain.TurnosEnRango) error) error {
+	if err := m.verificar(ctx, verificar); err != nil {
+		return err
+	}
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockTurnoRepository) GetSerie(ctx context.Context, id string) (*domain.Serie, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Serie), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockListaEsperaRepository struct {
+	mock.Mock
+}
+
+func (m *MockListaEsperaRepository) CreateOrUpdate(ctx context.Context, e *domain.EntradaEspera) (*domain.EntradaEspera, error) {
+	args := m.Called(ctx, e)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.EntradaEspera), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListaEsperaRepository) GetByID(ctx context.Context, id string) (*domain.EntradaEspera, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.EntradaEspera), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListaEsperaRepository) GetActivas(ctx context.Context) ([]*domain.EntradaEspera, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.EntradaEspera), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListaEsperaRepository) GetEsperando(ctx context.Context, fecha time.Time) ([]*domain.EntradaEspera, error) {
+	args := m.Called(ctx, fecha)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.EntradaEspera), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListaEsperaRepository) GuardarOferta(ctx context.Context, o *domain.Oferta, e *domain.EntradaEspera) error {
+	args := m.Called(ctx, o, e)
+	return args.Error(0)
+}
+
+func (m *MockListaEsperaRepository) GetOferta(ctx context.Context, id string) (*domain.Oferta, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Oferta), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListaEsperaRepository) GetOfertasVencidas(ctx context.Context, ahora time.Time) ([]*domain.Oferta, error) {
+	args := m.Called(ctx, ahora)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Oferta), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListaEsperaRepository) GetOfertasPendientes(ctx context.Context, desde, hasta, ahora time.Time) ([]*domain.Oferta, error) {
+	args := m.Called(ctx, desde, hasta, ahora)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Oferta), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// MockClienteService solo implementa lo que usa turnoService; el resto viene de la interfaz embebida.
+type MockClienteService struct {
+	cliente.ClienteService
+	mock.Mock
+}
+
+func (m *MockClienteService) GetByID(ctx context.Context, id string) (*domain.Cliente, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Cliente), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockClienteService) Restringir(ctx context.Context, id string, r domain.RestriccionCliente) (*domain.Cliente, error) {
+	args := m.Called(ctx, id, r)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Cliente), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// MockBloqueoService solo implementa lo que usa turnoService.
+type MockBloqueoService struct {
+	bloqueo.BloqueoService
+	mock.Mock
+}
+
+func (m *MockBloqueoService) GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Bloqueo, error) {
+	args := m.Called(ctx, desde, hasta)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.Bloqueo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockBloqueoService) Create(ctx context.Context, b *domain.Bloqueo) (*domain.Bloqueo, error) {
+	args := m.Called(ctx, b)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Bloqueo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockBloqueoService) Bloqueante(ctx context.Context, t *domain.Turno) (*domain.Bloqueo, error) {
+	args := m.Called(ctx, t)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.Bloqueo), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockNotificacionRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificacionRepository) Create(ctx context.Context, n *domain.Notificacion) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+type MockFichaRepository struct {
+	mock.Mock
+}
+
+func (m *MockFichaRepository) CreateOrUpdate(ctx context.Context, e *domain.EntradaFicha) (*domain.EntradaFicha, error) {
+	args := m.Called(ctx, e)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFichaRepository) Delete(ctx context.Context, clienteID, id string) error {
+	args := m.Called(ctx, clienteID, id)
+	return args.Error(0)
+}
+
+func (m *MockFichaRepository) GetByID(ctx context.Context, clienteID, id string) (*domain.EntradaFicha, error) {
+	args := m.Called(ctx, clienteID, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFichaRepository) GetByCliente(ctx context.Context, clienteID string) ([]*domain.EntradaFicha, error) {
+	args := m.Called(ctx, clienteID)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFichaRepository) UltimasFormulas(ctx context.Context, clienteIDs []string) (map[string]*domain.EntradaFicha, error) {
+	args := m.Called(ctx, clienteIDs)
+	if args.Get(0) != nil {
+		return args.Get(0).(map[string]*domain.EntradaFicha), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockHorarioService struct {
+	mock.Mock
+}
+
+func (m *MockHorarioService) Update(ctx context.Context, h *domain.HorarioLaboral) (*domain.HorarioLaboral, error) {
+	args := m.Called(ctx, h)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.HorarioLaboral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockHorarioService) GetByDia(ctx context.Context, dia time.Weekday) (*domain.HorarioLaboral, error) {
+	args := m.Called(ctx, dia)
+	if args.Get(0) != nil {
+		return args.Get(0).(*domain.HorarioLaboral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockHorarioService) GetAll(ctx context.Context) ([]*domain.HorarioLaboral, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]*domain.HorarioLaboral), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockHorarioService) Cubre(ctx context.Context, t *domain.Turno) (bool, error) {
+	args := m.Called(ctx, t)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestTurnoService_Create(t *testing.T) {
+	t.Run("Create Return Error Validate()", func(t *testing.T) {
+		s := turno.NewTurnoService(nil, nil)
+		res, err := s.Create(context.Background(), &domain.Turno{
+			ID:    "",
+			Fecha: time.Time{},
+			Hora:  domain.TimeOfDay{},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "fecha no puede ser cero")
+	})
+	t.Run("Un servicio repetido no se acepta", func(t *testing.T) {
+		mockCliente := new(MockClienteService)
+		s := turno.NewTurnoService(new(MockTurnoRepository), mockCliente)
+
+		res, err := s.ToDomain(context.Background(), &dto.TurnoRequest{
+			Fecha: "2025/06/02", Hora: "10:30", ClienteID: "123", ServicioIDs: []string{"corte", "color", "corte"},
+		})
+		assert.ErrorIs(t, err, domain.ErrServicioRepetido)
+		assert.ErrorContains(t, err, "corte")
+		assert.Nil(t, res)
+		mockCliente.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+	t.Run("Create asigna UUID si ID esta vacio", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy))
+		fechaprueba, _ := time.Parse("2006/01/02", "2025/08/15")
+		horaprueba, _ := domain.ParseTimeOfDay("10:30")
+		turnoNuevo := &domain.Turno{
+			ID:       "",
+			Fecha:    fechaprueba,
+			Hora:     horaprueba,
+			Duracion: domain.DuracionPorDefecto,
+			Cliente: domain.Cliente{
+				ID:                 "",
+				Nombre:             "Cliente Test",
+				Telefono:           "123456789",
+				PreferenciaHoraria: domain.PreferenciaHoraria(1),
+			},
+		}
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		//mock anything espera cualquier valor de tipo *domain.Turno
+		mockRepo.On("CreateOrUpdate", mock.Anything, mock.AnythingOfType("*domain.Turno")).Return(turnoNuevo, nil).Run(func(args mock.Arguments) {
+			turnoNuevo := args.Get(1).(*domain.Turno)
+			assert.NotEmpty(t, turnoNuevo.ID) // verifica que el ID no esté vacío, si no da error
+		})
+		s.Create(context.Background(), turnoNuevo)
+	})
+	//test driven table
+	tests := []struct {
+		name     string
+		mockData *domain.Turno
+		mockErr  error
+		WantErr  bool
+	}{
+		{"Success", makeTurno("01"), nil, false},
+		{"RepoError", makeTurno("01"), assert.AnError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setupTurnoServiceWithMock(t)
+			mockRepo.On("GetEnRango", ventana(tt.mockData)...).Return([]*domain.Turno{}, nil)
+			mockRepo.On("CreateOrUpdate", mock.Anything, tt.mockData).Return(tt.mockData, tt.mockErr)
+			got, err := s.Create(context.Background(), tt.mockData)
+
+			if tt.WantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockData, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// agendaEnMemoria guarda los turnos en memoria y serializa las escrituras como
+// el lock de la agenda del repositorio real.
+type agendaEnMemoria struct {
+	repository.TurnoRepository
+	mu       sync.Mutex
+	turnos   []*domain.Turno
+	lectores sync.WaitGroup
+}
+
+func (a *agendaEnMemoria) CreateOrUpdate(ctx context.Context, t *domain.Turno, verificar func(domain.TurnosEnRango) error) (*domain.Turno, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if verificar != nil {
+		if err := verificar(a.enRango); err != nil {
+			return nil, err
+		}
+	}
+	a.turnos = append(a.turnos, t)
+	return t, nil
+}
+
+// GetEnRango es la lectura sin lock: cada lector sigue recién cuando leyó el
+// otro, así las dos reservas ven la agenda antes de que alguna guarde.
+func (a *agendaEnMemoria) GetEnRango(ctx context.Context, desde, hasta time.Time) ([]*domain.Turno, error) {
+	a.mu.Lock()
+	res, err := a.enRango(desde, hasta)
+	a.mu.Unlock()
+	a.lectores.Done()
+	a.lectores.Wait()
+	return res, err
+}
+
+func (a *agendaEnMemoria) enRango(desde, hasta time.Time) ([]*domain.Turno, error) {
+	var res []*domain.Turno
+	for _, t := range a.turnos {
+		if t.Inicio().Before(hasta) && t.Fin().After(desde) {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func TestTurnoService_ReservasSimultaneas(t *testing.T) {
+	agenda := &agendaEnMemoria{}
+	agenda.lectores.Add(2)
+	s := turno.NewTurnoService(agenda, nil, turno.WithReloj(hoy))
+
+	errs := make(chan error, 2)
+	for _, cliente := range []string{"123", "456"} {
+		go func() {
+			nuevo := makeTurno("02")
+			nuevo.Cliente.ID = cliente
+			_, err := s.Create(context.Background(), nuevo)
+			errs <- err
+		}()
+	}
+	var conflictos int
+	for range 2 {
+		err := <-errs
+		var conflicto *domain.ConflictoTurnoError
+		if errors.As(err, &conflicto) {
+			conflictos++
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+	assert.Equal(t, 1, conflictos)
+	assert.Len(t, agenda.turnos, 1)
+}
+
+func TestTurnoService_Conflictos(t *testing.T) {
+	t.Run("Create devuelve ConflictoTurnoError si se superpone", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		nuevo := makeTurno("01")
+		existente := makeTurno("01")
+		existente.Hora = domain.TimeOfDay{Hour: 10, Minute: 0}
+		existente.Duracion = time.Hour // 10:00 a 11:00 pisa al nuevo de 10:30 a 11:00
+		mockRepo.On("GetEnRango", ventana(nuevo)...).Return([]*domain.Turno{existente}, nil)
+
+		res, err := s.Create(context.Background(), nuevo)
+		assert.Nil(t, res)
+		var conflicto *domain.ConflictoTurnoError
+		assert.ErrorAs(t, err, &conflicto)
+		assert.Equal(t, []string{existente.ID}, conflicto.IDs)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+	t.Run("Turnos consecutivos no se solapan", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		nuevo := makeTurno("01")
+		anterior := makeTurno("01")
+		anterior.Hora = domain.TimeOfDay{Hour: 10, Minute: 0} // termina 10:30, justo cuando empieza el nuevo
+		mockRepo.On("GetEnRango", ventana(nuevo)...).Return([]*domain.Turno{anterior}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+
+		_, err := s.Create(context.Background(), nuevo)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+	t.Run("Update ignora al propio turno", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		actual := makeTurno("01")
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockRepo.On("GetEnRango", ventana(actual)...).Return([]*domain.Turno{actual}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, actual).Return(actual, nil)
+
+		_, err := s.Update(context.Background(), actual)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+	t.Run("El margen general separa turnos consecutivos", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithMargen(domain.Margen{Despues: 10 * time.Minute}))
+		nuevo := makeTurno("01")
+		anterior := makeTurno("01")
+		anterior.Hora = domain.TimeOfDay{Hour: 10, Minute: 0} // termina 10:30, su limpieza sigue hasta 10:40
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{anterior}, nil)
+
+		_, err := s.Create(context.Background(), nuevo)
+		var conflicto *domain.ConflictoTurnoError
+		assert.ErrorAs(t, err, &conflicto)
+		assert.Equal(t, []string{anterior.ID}, conflicto.IDs)
+	})
+	t.Run("El margen del servicio reemplaza al general", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithMargen(domain.Margen{Despues: 10 * time.Minute}))
+		sinLimpieza := time.Duration(0)
+		nuevo := makeTurno("01")
+		anterior := makeTurno("01")
+		anterior.Hora = domain.TimeOfDay{Hour: 10, Minute: 0}
+		anterior.Servicios = []domain.Servicio{{ID: "corte", Duracion: 30 * time.Minute, MargenDespues: &sinLimpieza}}
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{anterior}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+
+		_, err := s.Create(context.Background(), nuevo)
+		assert.NoError(t, err)
+	})
+	t.Run("La preparación del turno siguiente también cuenta", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		preparacion := 15 * time.Minute
+		nuevo := makeTurno("01") // 10:30 a 11:00
+		siguiente := makeTurno("01")
+		siguiente.Hora = domain.TimeOfDay{Hour: 11, Minute: 10}
+		siguiente.Servicios = []domain.Servicio{{ID: "color", Duracion: 30 * time.Minute, MargenAntes: &preparacion}}
+		mockRepo.On("GetEnRango", ventana(nuevo)...).Return([]*domain.Turno{siguiente}, nil)
+
+		_, err := s.Create(context.Background(), nuevo)
+		var conflicto *domain.ConflictoTurnoError
+		assert.ErrorAs(t, err, &conflicto)
+	})
+	t.Run("Las fases de pose dejan lugar para otro turno", func(t *testing.T) {
+		// color de 10:00 a 11:30: aplicación 20', pose 40', enjuague y secado 30'
+		color := makeTurnoConID("color")
+		color.Hora = domain.TimeOfDay{Hour: 10, Minute: 0}
+		color.Duracion = 90 * time.Minute
+		color.Servicios = []domain.Servicio{{ID: "color", Duracion: 90 * time.Minute, Fases: []domain.Fase{
+			{Duracion: 20 * time.Minute},
+			{Duracion: 40 * time.Minute, Libre: true},
+			{Duracion: 30 * time.Minute},
+		}}}
+		tests := []struct {
+			name   string
+			hora   domain.TimeOfDay
+			margen domain.Margen
+			choca  bool
+		}{
+			{"Un corte entra en la pose", domain.TimeOfDay{Hour: 10, Minute: 20}, domain.Margen{}, false},
+			{"Pisa la aplicación", domain.TimeOfDay{Hour: 10, Minute: 10}, domain.Margen{}, true},
+			{"Pisa el enjuague", domain.TimeOfDay{Hour: 10, Minute: 40}, domain.Margen{}, true},
+			{"La limpieza del corte no entra en la pose", domain.TimeOfDay{Hour: 10, Minute: 30}, domain.Margen{Despues: 10 * time.Minute}, true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(MockTurnoRepository)
+				s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithMargen(tt.margen))
+				corte := makeTurno("01")
+				corte.Hora = tt.hora
+				mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{color}, nil)
+				mockRepo.On("CreateOrUpdate", mock.Anything, corte).Return(corte, nil).Maybe()
+
+				_, err := s.Create(context.Background(), corte)
+				if tt.choca {
+					var conflicto *domain.ConflictoTurnoError
+					assert.ErrorAs(t, err, &conflicto)
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		}
+	})
+	t.Run("Error del repositorio al buscar conflictos", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		nuevo := makeTurno("01")
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		res, err := s.Create(context.Background(), nuevo)
+		assert.Nil(t, res)
+		assert.Equal(t, assert.AnError, err)
+	})
+}
+
+func TestTurnoService_Horario(t *testing.T) {
+	t.Run("Create rechaza turnos fuera del horario", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario))
+		nuevo := makeTurno("01")
+		mockHorario.On("Cubre", mock.Anything, nuevo).Return(false, nil)
+
+		res, err := s.Create(context.Background(), nuevo)
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, domain.ErrFueraDeHorario)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+	t.Run("Update rechaza turnos fuera del horario", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario))
+		actual := makeTurno("01")
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockHorario.On("Cubre", mock.Anything, actual).Return(false, nil)
+
+		res, err := s.Update(context.Background(), actual)
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, domain.ErrFueraDeHorario)
+	})
+	t.Run("Create dentro del horario sigue con la reserva", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario))
+		nuevo := makeTurno("01")
+		mockHorario.On("Cubre", mock.Anything, nuevo).Return(true, nil)
+		mockRepo.On("GetEnRango", ventana(nuevo)...).Return([]*domain.Turno{}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+
+		_, err := s.Create(context.Background(), nuevo)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockHorario.AssertExpectations(t)
+	})
+}
+
+func TestTurnoService_PlazoReserva(t *testing.T) {
+	ahora := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	politica := domain.PoliticaReserva{AnticipacionMinima: 2 * time.Hour, AnticipacionMaxima: 60 * 24 * time.Hour}
+	setup := func(t *testing.T) (turno.TurnoService, *MockTurnoRepository) {
+		mockRepo := new(MockTurnoRepository)
+		return turno.NewTurnoService(mockRepo, nil,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithPoliticaReserva(politica)), mockRepo
+	}
+
+	tests := []struct {
+		name    string
+		mover   func(*domain.Turno)
+		wantErr error
+	}{
+		{"Turno que ya pasó", func(t *domain.Turno) { t.Fecha = t.Fecha.AddDate(0, 0, -1) }, domain.ErrTurnoPasado},
+		{"Turno que ya empezó", func(t *domain.Turno) { t.Hora = domain.TimeOfDay{Hour: 7, Minute: 45} }, domain.ErrTurnoPasado},
+		{"Sin la anticipación mínima", func(t *domain.Turno) { t.Hora = domain.TimeOfDay{Hour: 9, Minute: 30} }, domain.ErrAnticipacionMinima},
+		{"Más allá del plazo máximo", func(t *domain.Turno) { t.Fecha = t.Fecha.AddDate(0, 2, 15) }, domain.ErrAnticipacionExcedida},
+		{"Justo en la anticipación mínima", func(t *domain.Turno) { t.Hora = domain.TimeOfDay{Hour: 10, Minute: 0} }, nil},
+		{"Dentro del plazo", func(t *domain.Turno) {}, nil},
+	}
+	for _, tt := range tests {
+		t.Run("Create: "+tt.name, func(t *testing.T) {
+			s, mockRepo := setup(t)
+			nuevo := makeTurno("01")
+			tt.mover(nuevo)
+			if tt.wantErr == nil {
+				mockRepo.On("GetEnRango", ventana(nuevo)...).Return([]*domain.Turno{}, nil)
+				mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+			}
+
+			_, err := s.Create(context.Background(), nuevo)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Update sin mover el turno no controla el plazo", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		actual := makeTurno("01")
+		actual.Hora = domain.TimeOfDay{Hour: 9, Minute: 0}
+		editado := *actual
+		editado.Duracion = time.Hour
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockRepo.On("GetEnRango", ventana(&editado)...).Return([]*domain.Turno{actual}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, &editado).Return(&editado, nil)
+
+		_, err := s.Update(context.Background(), &editado)
+		assert.NoError(t, err)
+
+		editado.Hora = domain.TimeOfDay{Hour: 8, Minute: 30}
+		_, err = s.Update(context.Background(), &editado)
+		assert.ErrorIs(t, err, domain.ErrAnticipacionMinima)
+	})
+	t.Run("Reprogramar no mueve un turno al pasado", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		actual := makeTurno("02")
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+
+		_, err := s.Reprogramar(context.Background(), actual.ID, actual.Fecha.AddDate(0, 0, -2), actual.Hora)
+		assert.ErrorIs(t, err, domain.ErrTurnoPasado)
+		mockRepo.AssertNotCalled(t, "Reprogramar", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Una serie no reserva más allá del plazo máximo", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		regla, err := domain.ParseRRule("FREQ=WEEKLY;COUNT=12")
+		assert.NoError(t, err)
+
+		// el primero está dentro de los 60 días, el décimo (5 de agosto) no
+		serie, err := s.CrearSerie(context.Background(), makeTurno("03"), regla)
+		assert.Nil(t, serie)
+		assert.ErrorIs(t, err, domain.ErrAnticipacionExcedida)
+		assert.ErrorContains(t, err, "turno del 2025-08-05")
+		mockRepo.AssertNotCalled(t, "CrearSerie", mock.Anything, mock.Anything)
+	})
+	t.Run("Disponibles no ofrece horarios que no se pueden reservar", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		dia := makeTurno("01").Fecha
+		mockRepo.On("GetEnRango", delDia(dia)...).Return([]*domain.Turno{}, nil)
+
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: dia, Hasta: dia, Duracion: 30 * time.Minute, Intervalo: time.Hour,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "10:00", horas(res[0].Horarios)[0])
+	})
+	t.Run("Política inválida", func(t *testing.T) {
+		assert.Error(t, domain.PoliticaReserva{AnticipacionMinima: -time.Hour}.Validate())
+		assert.Error(t, domain.PoliticaReserva{AnticipacionMinima: 3 * time.Hour, AnticipacionMaxima: time.Hour}.Validate())
+		assert.NoError(t, politica.Validate())
+	})
+}
+
+func TestTurnoService_LimiteReservas(t *testing.T) {
+	ahora := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC) // domingo
+	filtro := domain.FiltroTurnos{
+		Desde:     time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		ClienteID: "123",
+		Estados:   domain.EstadosActivos,
+	}
+	setup := func(t *testing.T) (turno.TurnoService, *MockTurnoRepository) {
+		mockRepo := new(MockTurnoRepository)
+		return turno.NewTurnoService(mockRepo, nil,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithLimiteReservas(domain.LimiteReservas{Total: 2, PorSemana: 1})), mockRepo
+	}
+	el := func(id string, dia int, hora int) *domain.Turno {
+		o := makeTurnoConID(id)
+		o.Fecha = time.Date(2025, 6, dia, 0, 0, 0, 0, time.UTC)
+		o.Hora = domain.TimeOfDay{Hour: hora, Minute: 0}
+		return o
+	}
+
+	tests := []struct {
+		name    string
+		activos []*domain.Turno
+		wantErr bool
+	}{
+		{"Sin turnos por venir", []*domain.Turno{}, false},
+		{"Ya tiene uno esa semana", []*domain.Turno{el("a", 5, 10)}, true},
+		{"Llegó al total", []*domain.Turno{el("a", 10, 10), el("b", 17, 10)}, true},
+		{"Los que ya empezaron no cuentan", []*domain.Turno{el("a", 1, 7), el("b", 10, 10)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, mockRepo := setup(t)
+			nuevo := makeTurno("03") // martes
+			mockRepo.On("Buscar", mock.Anything, filtro).Return(tt.activos, nil)
+			if !tt.wantErr {
+				mockRepo.On("GetEnRango", ventana(nuevo)...).Return([]*domain.Turno{}, nil)
+				mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+			}
+
+			_, err := s.Create(context.Background(), nuevo)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrLimiteReservas)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("El administrador puede excederlo", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		nuevo := makeTurno("03")
+		mockRepo.On("GetEnRango", ventana(nuevo)...).Return([]*domain.Turno{}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+
+		_, err := s.Create(turno.PermitirExcederLimite(context.Background()), nuevo)
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "Buscar", mock.Anything, mock.Anything)
+	})
+	t.Run("Mover un turno dentro de su semana no lo cuenta dos veces", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		actual := el("x", 3, 10)
+		movido := *actual
+		movido.Fecha = actual.Fecha.AddDate(0, 0, 1)
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockRepo.On("Buscar", mock.Anything, filtro).Return([]*domain.Turno{actual}, nil)
+		mockRepo.On("GetEnRango", ventana(&movido)...).Return([]*domain.Turno{actual}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, &movido).Return(&movido, nil)
+
+		_, err := s.Update(context.Background(), &movido)
+		assert.NoError(t, err)
+	})
+	t.Run("Una serie cuenta todas sus repeticiones", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		regla, err := domain.ParseRRule("FREQ=WEEKLY;COUNT=3")
+		assert.NoError(t, err)
+		mockRepo.On("Buscar", mock.Anything, filtro).Return([]*domain.Turno{}, nil)
+
+		// cada repetición cae en otra semana, pero la tercera pasa el total de 2
+		serie, err := s.CrearSerie(context.Background(), makeTurno("03"), regla)
+		assert.Nil(t, serie)
+		assert.ErrorIs(t, err, domain.ErrLimiteReservas)
+		assert.ErrorContains(t, err, "turno del 2025-06-17")
+		mockRepo.AssertNotCalled(t, "CrearSerie", mock.Anything, mock.Anything)
+	})
+	t.Run("Mover una serie controla cada repetición", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithLimiteReservas(domain.LimiteReservas{PorSemana: 1}))
+		primero, segundo := el("x1", 3, 10), el("x2", 10, 10)
+		serie := &domain.Serie{ID: "s1", ClienteID: "123", Turnos: []*domain.Turno{primero, segundo}}
+		for _, o := range serie.Turnos {
+			o.SerieID = serie.ID
+		}
+		otro := el("a", 12, 10) // jueves, la misma semana que el segundo
+		mockRepo.On("GetByID", mock.Anything, primero.ID).Return(primero, nil)
+		mockRepo.On("GetSerie", mock.Anything, "s1").Return(serie, nil)
+		mockRepo.On("Buscar", mock.Anything, filtro).Return([]*domain.Turno{primero, segundo, otro}, nil)
+
+		// el primero pasa al miércoles 4 sin problema, pero el segundo cae el 11
+		cambio := *primero
+		cambio.Fecha = primero.Fecha.AddDate(0, 0, 1)
+		got, err := s.ActualizarSerie(context.Background(), &cambio, domain.TodaLaSerie)
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, domain.ErrLimiteReservas)
+		assert.ErrorContains(t, err, "turno del 2025-06-11")
+		mockRepo.AssertNotCalled(t, "GuardarVarios", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTurnoService_Disponibles(t *testing.T) {
+	lunes, _ := time.Parse("2006/01/02", "2025/06/02")
+	semana := []*domain.HorarioLaboral{
+		domain.NewHorarioLaboral(time.Monday, []domain.IntervaloHorario{
+			{Desde: domain.TimeOfDay{Hour: 9, Minute: 0}, Hasta: domain.TimeOfDay{Hour: 11, Minute: 0}},
+			{Desde: domain.TimeOfDay{Hour: 16, Minute: 0}, Hasta: domain.TimeOfDay{Hour: 17, Minute: 0}},
+		}),
+	}
+	ocupado := makeTurno("02")
+	ocupado.Hora = domain.TimeOfDay{Hour: 9, Minute: 30} // 09:30 a 10:00
+
+	setup := func(t *testing.T) (turno.TurnoService, *MockTurnoRepository) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		mockHorario.On("GetAll", mock.Anything).Return(semana, nil)
+		return turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario)), mockRepo
+	}
+
+	t.Run("Excluye turnos ocupados y respeta las franjas", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		mockRepo.On("GetEnRango", delDia(lunes)...).Return([]*domain.Turno{ocupado}, nil)
+
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: lunes, Hasta: lunes, Duracion: 30 * time.Minute,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, []string{"09:00", "10:00", "10:15", "10:30", "16:00", "16:15", "16:30"}, horas(res[0].Horarios))
+	})
+	t.Run("Filtra por preferencia horaria", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		mockRepo.On("GetEnRango", delDia(lunes)...).Return([]*domain.Turno{}, nil)
+		tarde := domain.PreferenciaHoraria(domain.Tarde)
+
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: lunes, Hasta: lunes, Duracion: 30 * time.Minute, Intervalo: 30 * time.Minute, Preferencia: &tarde,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"16:00", "16:30"}, horas(res[0].Horarios))
+	})
+	t.Run("Los bloqueos no se ofrecen", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		mockBloqueo := new(MockBloqueoService)
+		mockHorario.On("GetAll", mock.Anything).Return(semana, nil)
+		// trámite semanal de los lunes que empezó la semana anterior
+		tramite := domain.NewBloqueo("b1", lunes.AddDate(0, 0, -7),
+			domain.TimeOfDay{Hour: 16, Minute: 0}, domain.TimeOfDay{Hour: 16, Minute: 30}, "banco", true, time.Time{})
+		mockBloqueo.On("GetEnRango", mock.Anything, lunes, lunes.AddDate(0, 0, 1)).Return([]*domain.Bloqueo{tramite}, nil)
+		mockRepo.On("GetEnRango", delDia(lunes)...).Return([]*domain.Turno{}, nil)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario), turno.WithBloqueoService(mockBloqueo))
+		tarde := domain.PreferenciaHoraria(domain.Tarde)
+
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: lunes, Hasta: lunes, Duracion: 30 * time.Minute, Intervalo: 15 * time.Minute, Preferencia: &tarde,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"16:30"}, horas(res[0].Horarios))
+	})
+	t.Run("El margen deja libre el tiempo de limpieza", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		mockHorario.On("GetAll", mock.Anything).Return(semana, nil)
+		mockRepo.On("GetEnRango", delDia(lunes)...).Return([]*domain.Turno{ocupado}, nil)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario),
+			turno.WithMargen(domain.Margen{Antes: 5 * time.Minute, Despues: 10 * time.Minute}))
+		manana := domain.PreferenciaHoraria(domain.Mañana)
+
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: lunes, Hasta: lunes, Duracion: 30 * time.Minute, Intervalo: 15 * time.Minute, Preferencia: &manana,
+		})
+		assert.NoError(t, err)
+		// ocupado va de 9:30 a 10:00 y tiene tomada la estación de 9:25 a 10:10
+		assert.Equal(t, []string{"10:15", "10:30"}, horas(res[0].Horarios))
+	})
+	t.Run("Cuenta los turnos del día anterior que pasan la medianoche", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		mockHorario.On("GetAll", mock.Anything).Return([]*domain.HorarioLaboral{
+			domain.NewHorarioLaboral(time.Monday, []domain.IntervaloHorario{
+				{Desde: domain.TimeOfDay{Hour: 0, Minute: 0}, Hasta: domain.TimeOfDay{Hour: 2, Minute: 0}},
+			}),
+		}, nil)
+		trasnoche := makeTurno("01") // domingo de 23:00 a 00:30 del lunes
+		trasnoche.Hora = domain.TimeOfDay{Hour: 23, Minute: 0}
+		trasnoche.Duracion = 90 * time.Minute
+		mockRepo.On("GetEnRango", delDia(lunes)...).Return([]*domain.Turno{trasnoche}, nil)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario))
+
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: lunes, Hasta: lunes, Duracion: 30 * time.Minute, Intervalo: 30 * time.Minute,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"00:30", "01:00", "01:30"}, horas(res[0].Horarios))
+	})
+	t.Run("Día sin horario no ofrece nada ni consulta turnos", func(t *testing.T) {
+		s, mockRepo := setup(t)
+		domingo := lunes.AddDate(0, 0, -1)
+
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: domingo, Hasta: domingo, Duracion: 30 * time.Minute,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, res[0].Horarios)
+		mockRepo.AssertNotCalled(t, "GetEnRango", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Error si falta la duración", func(t *testing.T) {
+		s, _ := setup(t)
+		res, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{Desde: lunes, Hasta: lunes})
+		assert.Nil(t, res)
+		assert.EqualError(t, err, "duración inválida")
+	})
+}
+
+func TestTurnoService_Agenda(t *testing.T) {
+	lunes, _ := time.Parse("2006/01/02", "2025/06/02")
+	domingo := lunes.AddDate(0, 0, 6)
+	semana := []*domain.HorarioLaboral{
+		domain.NewHorarioLaboral(time.Monday, []domain.IntervaloHorario{
+			{Desde: domain.TimeOfDay{Hour: 9, Minute: 0}, Hasta: domain.TimeOfDay{Hour: 11, Minute: 0}},
+			{Desde: domain.TimeOfDay{Hour: 16, Minute: 0}, Hasta: domain.TimeOfDay{Hour: 17, Minute: 0}},
+		}),
+	}
+	tramite := domain.NewBloqueo("b1", lunes,
+		domain.TimeOfDay{Hour: 16, Minute: 0}, domain.TimeOfDay{Hour: 16, Minute: 30}, "banco", false, time.Time{})
+	corto := makeTurnoConID("corto")
+	corto.Fecha = lunes
+	largo := makeTurnoConID("largo")
+	largo.Fecha, largo.Hora, largo.Duracion = lunes, domain.TimeOfDay{Hour: 9, Minute: 0}, time.Hour
+	miercoles := makeTurnoConID("miercoles")
+	miercoles.Fecha = lunes.AddDate(0, 0, 2)
+
+	mockRepo := new(MockTurnoRepository)
+	mockHorario := new(MockHorarioService)
+	mockBloqueo := new(MockBloqueoService)
+	mockRepo.On("GetEntreFechas", mock.Anything, lunes, domingo, domain.EstadosQueOcupan).
+		Return([]*domain.Turno{largo, corto, miercoles}, nil)
+	mockHorario.On("GetAll", mock.Anything).Return(semana, nil)
+	mockBloqueo.On("GetEnRango", mock.Anything, lunes, domingo.AddDate(0, 0, 1)).Return([]*domain.Bloqueo{tramite}, nil)
+	s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithHorarioService(mockHorario), turno.WithBloqueoService(mockBloqueo))
+
+	desde, hasta := domain.SemanaDe(lunes.AddDate(0, 0, 3))
+	assert.Equal(t, lunes, desde)
+	assert.Equal(t, domingo, hasta)
+
+	res, err := s.Agenda(context.Background(), desde, hasta)
+	assert.NoError(t, err)
+	assert.Len(t, res.Dias, 7)
+
+	lun := res.Dias[0]
+	assert.Equal(t, []*domain.Turno{largo, corto}, lun.Turnos)
+	assert.Equal(t, 90*time.Minute, lun.Reservado)
+	assert.Equal(t, 150*time.Minute, lun.Abierto) // 3 horas de atención menos media hora de trámite
+	assert.InDelta(t, 0.6, lun.Ocupacion(), 0.001)
+
+	mie := res.Dias[2]
+	assert.Equal(t, []*domain.Turno{miercoles}, mie.Turnos)
+	assert.Zero(t, mie.Abierto)
+	assert.Zero(t, mie.Ocupacion())
+
+	assert.Empty(t, res.Dias[6].Turnos)
+
+	desde, hasta = domain.MesDe(time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), desde)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), hasta)
+}
+
+func TestTurnoService_AgendaFormulas(t *testing.T) {
+	// la semana de hoy(): del lunes 28/04 al domingo 04/05
+	desde, hasta := domain.SemanaDe(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC))
+	pasado := makeTurnoConID("pasado")
+	pasado.Fecha = desde
+	proximo := makeTurnoConID("proximo")
+	proximo.Fecha = desde.AddDate(0, 0, 4)
+	otroCliente := makeTurnoConID("otro")
+	otroCliente.Fecha = desde.AddDate(0, 0, 5)
+	otroCliente.Cliente.ID = "456"
+	confirmado := makeTurnoConID("confirmado")
+	confirmado.Fecha, confirmado.Estado = desde.AddDate(0, 0, 6), domain.Confirmado
+	formula := &domain.EntradaFicha{ID: "f1", ClienteID: "123", Formula: "7.1 + 7.0", Oxidante: 20}
+
+	mockRepo := new(MockTurnoRepository)
+	mockFicha := new(MockFichaRepository)
+	mockRepo.On("GetEntreFechas", mock.Anything, desde, hasta, domain.EstadosQueOcupan).
+		Return([]*domain.Turno{pasado, proximo, otroCliente, confirmado}, nil)
+	mockFicha.On("UltimasFormulas", mock.Anything, []string{"123", "456"}).
+		Return(map[string]*domain.EntradaFicha{"123": formula}, nil)
+	s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithFichas(mockFicha))
+
+	res, err := s.Agenda(context.Background(), desde, hasta)
+	assert.NoError(t, err)
+	// el turno que ya pasó no la muestra, y el otro cliente no tiene fórmula cargada
+	assert.Equal(t, map[string]*domain.EntradaFicha{"proximo": formula, "confirmado": formula}, res.Formulas)
+	mockFicha.AssertExpectations(t)
+}
+
+func TestTurnoService_Bloqueos(t *testing.T) {
+	t.Run("Create rechaza turnos sobre un bloqueo", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockBloqueo := new(MockBloqueoService)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithBloqueoService(mockBloqueo))
+		nuevo := makeTurno("02")
+		almuerzo := domain.NewBloqueo("b1", nuevo.Fecha,
+			domain.TimeOfDay{Hour: 10, Minute: 0}, domain.TimeOfDay{Hour: 11, Minute: 0}, "almuerzo", false, time.Time{})
+		mockBloqueo.On("Bloqueante", mock.Anything, nuevo).Return(almuerzo, nil)
+
+		got, err := s.Create(context.Background(), nuevo)
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, domain.ErrAgendaBloqueada)
+		assert.ErrorContains(t, err, "almuerzo")
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTurnoService_Reprogramar(t *testing.T) {
+	ahora := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	reloj := func() time.Time { return ahora }
+	nuevaFecha, _ := time.Parse("2006/01/02", "2025/06/05")
+	nuevaHora := domain.TimeOfDay{Hour: 15, Minute: 0}
+
+	t.Run("Mueve el turno y deja el horario anterior en el historial", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(reloj))
+		actual := makeTurno("02")
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		evento := domain.EventoTurno{
+			TurnoID:        actual.ID,
+			EstadoAnterior: domain.Pendiente,
+			Estado:         domain.Pendiente,
+			Fecha:          ahora,
+			Detalle:        "reprogramado, antes era el 2025/06/02 a las 10:30",
+		}
+		movido := &domain.Turno{Fecha: nuevaFecha, Hora: nuevaHora, Duracion: actual.Duracion}
+		mockRepo.On("GetEnRango", ventana(movido)...).Return([]*domain.Turno{actual}, nil)
+		mockRepo.On("Reprogramar", mock.Anything, actual, evento).Return(nil)
+
+		res, err := s.Reprogramar(context.Background(), actual.ID, nuevaFecha, nuevaHora)
+		assert.NoError(t, err)
+		assert.Equal(t, nuevaFecha, res.Fecha)
+		assert.Equal(t, nuevaHora, res.Hora)
+		mockRepo.AssertExpectations(t)
+	})
+	t.Run("Conflicto si el lugar se ocupó mientras tanto", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(reloj))
+		actual := makeTurno("02")
+		otro := makeTurnoConID("otro")
+		otro.Fecha, otro.Hora = nuevaFecha, nuevaHora
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{otro}, nil)
+
+		res, err := s.Reprogramar(context.Background(), actual.ID, nuevaFecha, nuevaHora)
 		assert.Nil(t, res)
-		assert.EqualError(t, err, "fecha no puede ser cero")
+		var conflicto *domain.ConflictoTurnoError
+		assert.ErrorAs(t, err, &conflicto)
+		assert.Equal(t, []string{"otro"}, conflicto.IDs)
 	})
-	t.Run("Create asigna UUID si ID esta vacio", func(t *testing.T) {
+	t.Run("Un turno completado no se reprograma", func(t *testing.T) {
 		mockRepo := new(MockTurnoRepository)
-		s := turno.NewTurnoService(mockRepo, nil)
-		fechaprueba, _ := time.Parse("2006/01/02", "2025/08/15")
-		horaprueba, _ := domain.ParseTimeOfDay("10:30")
-		turnoNuevo := &domain.Turno{
-			ID:    "",
-			Fecha: fechaprueba,
-			Hora:  horaprueba,
-			Cliente: domain.Cliente{
-				ID:                 "",
-				Nombre:             "Cliente Test",
-				Telefono:           "123456789",
-				PreferenciaHoraria: domain.PreferenciaHoraria(1),
-			},
-		}
-		//mock anything espera cualquier valor de tipo *domain.Turno
-		mockRepo.On("CreateOrUpdate", mock.Anything, mock.AnythingOfType("*domain.Turno")).Return(turnoNuevo, nil).Run(func(args mock.Arguments) {
-			turnoNuevo := args.Get(1).(*domain.Turno)
-			assert.NotEmpty(t, turnoNuevo.ID) // verifica que el ID no esté vacío, si no da error
-		})
-		s.Create(context.Background(), turnoNuevo)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(reloj))
+		actual := makeTurno("02")
+		actual.Estado = domain.Completado
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+
+		_, err := s.Reprogramar(context.Background(), actual.ID, nuevaFecha, nuevaHora)
+		assert.ErrorIs(t, err, domain.ErrTransicionInvalida)
+		mockRepo.AssertNotCalled(t, "Reprogramar", mock.Anything, mock.Anything, mock.Anything)
 	})
-	//test driven table
+}
+
+func TestTurnoService_Estados(t *testing.T) {
+	ahora := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	reloj := func() time.Time { return ahora }
+
 	tests := []struct {
-		name     string
-		mockData *domain.Turno
-		mockErr  error
-		WantErr  bool
+		name    string
+		inicial domain.EstadoTurno
+		accion  func(s turno.TurnoService, id string) (*domain.Turno, error)
+		want    domain.EstadoTurno
+		wantErr bool
 	}{
-		{"Success", makeTurno("01"), nil, false},
-		{"RepoError", makeTurno("01"), assert.AnError, true},
+		{"Confirmar pendiente", domain.Pendiente, func(s turno.TurnoService, id string) (*domain.Turno, error) {
+			return s.Confirmar(context.Background(), id)
+		}, domain.Confirmado, false},
+		{"Completar confirmado", domain.Confirmado, func(s turno.TurnoService, id string) (*domain.Turno, error) {
+			return s.Completar(context.Background(), id)
+		}, domain.Completado, false},
+		{"Ausente confirmado", domain.Confirmado, func(s turno.TurnoService, id string) (*domain.Turno, error) {
+			return s.MarcarAusente(context.Background(), id)
+		}, domain.Ausente, false},
+		{"Cancelado no se puede completar", domain.Cancelado, func(s turno.TurnoService, id string) (*domain.Turno, error) {
+			return s.Completar(context.Background(), id)
+		}, domain.Cancelado, true},
+		{"Completado no se puede cancelar", domain.Completado, func(s turno.TurnoService, id string) (*domain.Turno, error) {
+			return s.Cancelar(context.Background(), id, domain.Cancelacion{Origen: domain.CanceladoPorNegocio, Motivo: "error de carga"})
+		}, domain.Completado, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, mockRepo := setupTurnoServiceWithMock(t)
-			mockRepo.On("CreateOrUpdate", mock.Anything, tt.mockData).Return(tt.mockData, tt.mockErr)
-			got, err := s.Create(context.Background(), tt.mockData)
+			mockRepo := new(MockTurnoRepository)
+			s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(reloj))
+			actual := makeTurno("01")
+			actual.Estado = tt.inicial
+			mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+			mockRepo.On("CambiarEstado", mock.Anything, actual, domain.EventoTurno{
+				TurnoID:        actual.ID,
+				EstadoAnterior: tt.inicial,
+				Estado:         tt.want,
+				Fecha:          ahora,
+			}).Return(nil)
 
-			if tt.WantErr {
-				assert.Error(t, err)
+			got, err := tt.accion(s, actual.ID)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrTransicionInvalida)
+				assert.Nil(t, got)
+				mockRepo.AssertNotCalled(t, "CambiarEstado", mock.Anything, mock.Anything, mock.Anything)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.mockData, got)
+				assert.Equal(t, tt.want, got.Estado)
+				mockRepo.AssertExpectations(t)
 			}
-			mockRepo.AssertExpectations(t)
 		})
 	}
+	t.Run("Create siempre arranca en pendiente", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		nuevo := makeTurno("01")
+		nuevo.Estado = domain.Completado
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+
+		got, err := s.Create(context.Background(), nuevo)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Pendiente, got.Estado)
+	})
+	t.Run("Update conserva el estado guardado", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		guardado := makeTurno("01")
+		guardado.Estado = domain.Confirmado
+		editado := makeTurnoConID(guardado.ID)
+		mockRepo.On("GetByID", mock.Anything, guardado.ID).Return(guardado, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, editado).Return(editado, nil)
+
+		got, err := s.Update(context.Background(), editado)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Confirmado, got.Estado)
+	})
+	t.Run("Update rechaza turnos cerrados", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		guardado := makeTurno("01")
+		guardado.Estado = domain.Cancelado
+		mockRepo.On("GetByID", mock.Anything, guardado.ID).Return(guardado, nil)
+
+		got, err := s.Update(context.Background(), makeTurnoConID(guardado.ID))
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, domain.ErrTransicionInvalida)
+	})
+	t.Run("GetAll pasa el filtro de estados al repositorio", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		filtro := []domain.EstadoTurno{domain.Confirmado}
+		mockRepo.On("GetAll", mock.Anything, filtro).Return([]*domain.Turno{}, nil)
+
+		_, err := s.GetAll(context.Background(), domain.Confirmado)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
 func TestTurnoService_Update(t *testing.T) {
@@ -130,9 +1280,10 @@ func TestTurnoService_Update(t *testing.T) {
 	t.Run("Validar error si ID esta vacio", func(t *testing.T) {
 		s := turno.NewTurnoService(nil, nil)
 		res, err := s.Update(context.Background(), &domain.Turno{
-			ID:    "",
-			Fecha: time.Now(),
-			Hora:  domain.TimeOfDay{},
+			ID:       "",
+			Fecha:    time.Now(),
+			Hora:     domain.TimeOfDay{},
+			Duracion: domain.DuracionPorDefecto,
 			Cliente: domain.Cliente{
 				ID:                 "123",
 				Nombre:             "Cliente Test",
@@ -140,8 +1291,6 @@ func TestTurnoService_Update(t *testing.T) {
 				PreferenciaHoraria: domain.PreferenciaHoraria(1),
 			},
 		})
-		assert.NoError(t, err)
-		assert.NotEmpty(t, res.ID)
 		assert.Error(t, err)
 		assert.Nil(t, res)
 		assert.EqualError(t, err, "ID requerido para actualizar")
@@ -161,6 +1310,8 @@ func TestTurnoService_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s, mockRepo := setupTurnoServiceWithMock(t)
+			mockRepo.On("GetByID", mock.Anything, tt.mockData.ID).Return(tt.mockData, nil)
+			mockRepo.On("GetEnRango", ventana(tt.mockData)...).Return([]*domain.Turno{}, nil)
 			mockRepo.On("CreateOrUpdate", mock.Anything, tt.mockData).Return(tt.mockData, tt.mockErr)
 			got, err := s.Update(context.Background(), tt.mockData)
 
@@ -175,6 +1326,670 @@ func TestTurnoService_Update(t *testing.T) {
 	}
 }
 
+func TestTurnoService_Cancelar(t *testing.T) {
+	// makeTurno("01") empieza el 2025-06-01 a las 10:30
+	inicio := time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC)
+	politica := domain.PoliticaCancelacion{AnticipacionMinima: 12 * time.Hour}
+
+	tests := []struct {
+		name        string
+		ahora       time.Time
+		rechazar    bool
+		cancelacion domain.Cancelacion
+		wantDetalle string
+		wantTardia  bool
+		wantErr     error
+	}{
+		{
+			name:        "Cliente con anticipación suficiente",
+			ahora:       inicio.Add(-24 * time.Hour),
+			cancelacion: domain.Cancelacion{Origen: domain.CanceladoPorCliente, Motivo: "viaje"},
+			wantDetalle: "cancelado por cliente: viaje",
+		},
+		{
+			name:        "Cliente tarde queda marcada",
+			ahora:       inicio.Add(-2 * time.Hour),
+			cancelacion: domain.Cancelacion{Origen: domain.CanceladoPorCliente, Motivo: "enfermedad"},
+			wantDetalle: "cancelado por cliente: enfermedad (cancelación tardía)",
+			wantTardia:  true,
+		},
+		{
+			name:        "Cliente tarde se rechaza si la política lo pide",
+			ahora:       inicio.Add(-2 * time.Hour),
+			rechazar:    true,
+			cancelacion: domain.Cancelacion{Origen: domain.CanceladoPorCliente, Motivo: "enfermedad"},
+			wantErr:     domain.ErrCancelacionTardia,
+		},
+		{
+			name:        "El negocio puede cancelar sobre la hora",
+			ahora:       inicio.Add(-time.Hour),
+			rechazar:    true,
+			cancelacion: domain.Cancelacion{Origen: domain.CanceladoPorNegocio, Motivo: "corte de luz"},
+			wantDetalle: "cancelado por negocio: corte de luz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTurnoRepository)
+			p := politica
+			p.RechazarTardias = tt.rechazar
+			s := turno.NewTurnoService(mockRepo, nil,
+				turno.WithReloj(func() time.Time { return tt.ahora }),
+				turno.WithPoliticaCancelacion(p))
+			actual := makeTurno("01")
+			mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+			mockRepo.On("CambiarEstado", mock.Anything, actual, domain.EventoTurno{
+				TurnoID:        actual.ID,
+				EstadoAnterior: domain.Pendiente,
+				Estado:         domain.Cancelado,
+				Fecha:          tt.ahora,
+				Detalle:        tt.wantDetalle,
+			}).Return(nil)
+
+			got, err := s.Cancelar(context.Background(), actual.ID, tt.cancelacion)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				mockRepo.AssertNotCalled(t, "CambiarEstado", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.Cancelado, got.Estado)
+			assert.Equal(t, tt.wantTardia, got.Cancelacion.Tardia)
+			assert.Equal(t, tt.ahora, got.Cancelacion.Fecha)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+	t.Run("Motivo obligatorio", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		got, err := s.Cancelar(context.Background(), "1", domain.Cancelacion{Origen: domain.CanceladoPorCliente})
+		assert.Nil(t, got)
+		assert.EqualError(t, err, "motivo de cancelación requerido")
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTurnoService_Ausencias(t *testing.T) {
+	ahora := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	regla := domain.ReglaAusencias{Maximo: 3, Ventana: 90 * 24 * time.Hour, Accion: domain.Bloqueado}
+	setup := func(t *testing.T) (turno.TurnoService, *MockTurnoRepository, *MockClienteService) {
+		mockRepo := new(MockTurnoRepository)
+		mockCliente := new(MockClienteService)
+		s := turno.NewTurnoService(mockRepo, mockCliente,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithReglaAusencias(regla))
+		return s, mockRepo, mockCliente
+	}
+	marcar := func(mockRepo *MockTurnoRepository) *domain.Turno {
+		actual := makeTurno("01")
+		actual.Estado = domain.Confirmado
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockRepo.On("CambiarEstado", mock.Anything, actual, mock.Anything).Return(nil)
+		return actual
+	}
+
+	t.Run("Bloquea al llegar al máximo dentro de la ventana", func(t *testing.T) {
+		s, mockRepo, mockCliente := setup(t)
+		actual := marcar(mockRepo)
+		mockCliente.On("GetByID", mock.Anything, "123").Return(&domain.Cliente{ID: "123"}, nil)
+		mockRepo.On("ContarIncumplimientos", mock.Anything, "123", ahora.Add(-regla.Ventana)).Return(3, 0, nil)
+		mockCliente.On("Restringir", mock.Anything, "123", domain.Bloqueado).Return(&domain.Cliente{ID: "123"}, nil)
+
+		_, err := s.MarcarAusente(context.Background(), actual.ID)
+		assert.NoError(t, err)
+		mockCliente.AssertExpectations(t)
+	})
+	t.Run("Debajo del máximo no restringe", func(t *testing.T) {
+		s, mockRepo, mockCliente := setup(t)
+		actual := marcar(mockRepo)
+		mockCliente.On("GetByID", mock.Anything, "123").Return(&domain.Cliente{ID: "123"}, nil)
+		mockRepo.On("ContarIncumplimientos", mock.Anything, "123", mock.Anything).Return(2, 0, nil)
+
+		_, err := s.MarcarAusente(context.Background(), actual.ID)
+		assert.NoError(t, err)
+		mockCliente.AssertNotCalled(t, "Restringir", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Solo cuenta desde que se levantó la última restricción", func(t *testing.T) {
+		s, mockRepo, mockCliente := setup(t)
+		actual := marcar(mockRepo)
+		levantada := ahora.Add(-10 * 24 * time.Hour)
+		mockCliente.On("GetByID", mock.Anything, "123").Return(&domain.Cliente{ID: "123", RestriccionLevantada: levantada}, nil)
+		mockRepo.On("ContarIncumplimientos", mock.Anything, "123", levantada).Return(1, 0, nil)
+
+		_, err := s.MarcarAusente(context.Background(), actual.ID)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+	t.Run("Create rechaza clientes bloqueados", func(t *testing.T) {
+		s, mockRepo, _ := setup(t)
+		nuevo := makeTurno("01")
+		nuevo.Cliente.Restriccion = domain.Bloqueado
+
+		got, err := s.Create(context.Background(), nuevo)
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, domain.ErrClienteBloqueado)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+	t.Run("Create exige seña si el cliente la debe", func(t *testing.T) {
+		s, mockRepo, _ := setup(t)
+		nuevo := makeTurno("02")
+		nuevo.Cliente.Restriccion = domain.RequiereSena
+
+		_, err := s.Create(context.Background(), nuevo)
+		assert.ErrorIs(t, err, domain.ErrSenaRequerida)
+
+		nuevo.Sena = 2000
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockRepo.On("CreateOrUpdate", mock.Anything, nuevo).Return(nuevo, nil)
+		_, err = s.Create(context.Background(), nuevo)
+		assert.NoError(t, err)
+	})
+}
+
+func TestTurnoService_Serie(t *testing.T) {
+	t.Run("ParseRRule rechaza reglas fuera del subconjunto", func(t *testing.T) {
+		for _, rrule := range []string{
+			"FREQ=DAILY;COUNT=3",
+			"FREQ=WEEKLY",
+			"FREQ=WEEKLY;COUNT=3;UNTIL=20250901",
+			"FREQ=WEEKLY;INTERVAL=0;COUNT=3",
+			"FREQ=WEEKLY;BYDAY=MO;COUNT=3",
+			"FREQ=WEEKLY;COUNT=100",
+		} {
+			_, err := domain.ParseRRule(rrule)
+			assert.Error(t, err, rrule)
+		}
+	})
+	t.Run("CrearSerie genera un turno cada INTERVAL semanas", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		regla, err := domain.ParseRRule("RRULE:FREQ=WEEKLY;INTERVAL=3;COUNT=3")
+		assert.NoError(t, err)
+		primero := makeTurno("02")
+		primero.Sena = 1500
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockRepo.On("CrearSerie", mock.Anything, mock.AnythingOfType("*domain.Serie")).Return(nil)
+
+		serie, err := s.CrearSerie(context.Background(), primero, regla)
+		assert.NoError(t, err)
+		assert.Len(t, serie.Turnos, 3)
+		for i, o := range serie.Turnos {
+			assert.Equal(t, primero.Fecha.AddDate(0, 0, 21*i), o.Fecha)
+			assert.Equal(t, serie.ID, o.SerieID)
+			assert.Equal(t, domain.Pendiente, o.Estado)
+		}
+		assert.Equal(t, 1500.0, serie.Turnos[0].Sena)
+		assert.Zero(t, serie.Turnos[1].Sena)
+	})
+	t.Run("CrearSerie con UNTIL incluye la última fecha", func(t *testing.T) {
+		regla, err := domain.ParseRRule("FREQ=WEEKLY;INTERVAL=2;UNTIL=20250629")
+		assert.NoError(t, err)
+		fechas, err := regla.Fechas(makeTurno("01").Fecha)
+		assert.NoError(t, err)
+		assert.Len(t, fechas, 3) // 1, 15 y 29 de junio
+	})
+	t.Run("CrearSerie no guarda nada si una ocurrencia choca", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		regla, _ := domain.ParseRRule("FREQ=WEEKLY;COUNT=2")
+		primero := makeTurno("02")
+		ocupado := makeTurno("09")
+		mockRepo.On("GetEnRango", ventana(primero)...).Return([]*domain.Turno{}, nil)
+		mockRepo.On("GetEnRango", ventana(ocupado)...).Return([]*domain.Turno{ocupado}, nil)
+
+		serie, err := s.CrearSerie(context.Background(), primero, regla)
+		assert.Nil(t, serie)
+		var conflicto *domain.ConflictoTurnoError
+		assert.ErrorAs(t, err, &conflicto)
+		assert.Equal(t, []string{ocupado.ID}, conflicto.IDs)
+		mockRepo.AssertNotCalled(t, "CrearSerie", mock.Anything, mock.Anything)
+	})
+
+	// serie de tres turnos, cada tres semanas, con el primero ya completado
+	makeSerie := func() *domain.Serie {
+		serie := &domain.Serie{ID: "s1", ClienteID: "123"}
+		for i := 0; i < 3; i++ {
+			o := makeTurno("02")
+			o.Fecha = o.Fecha.AddDate(0, 0, 21*i)
+			o.SerieID = serie.ID
+			serie.Turnos = append(serie.Turnos, o)
+		}
+		serie.Turnos[0].Estado = domain.Completado
+		return serie
+	}
+	t.Run("ActualizarSerie con siguientes corre este y los posteriores", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		serie := makeSerie()
+		elegido := serie.Turnos[1]
+		mockRepo.On("GetByID", mock.Anything, elegido.ID).Return(elegido, nil)
+		mockRepo.On("GetSerie", mock.Anything, "s1").Return(serie, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockRepo.On("GuardarVarios", mock.Anything, mock.Anything).Return(nil)
+
+		cambio := makeTurnoConID(elegido.ID)
+		cambio.Fecha = elegido.Fecha.AddDate(0, 0, 1)
+		cambio.Hora = domain.TimeOfDay{Hour: 17, Minute: 0}
+		got, err := s.ActualizarSerie(context.Background(), cambio, domain.EsteYSiguientes)
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, serie.Turnos[2].Fecha.AddDate(0, 0, 1), got[1].Fecha)
+		for _, o := range got {
+			assert.Equal(t, "17:00", o.Hora.String())
+			assert.Equal(t, "s1", o.SerieID)
+		}
+		assert.Equal(t, "10:30", serie.Turnos[0].Hora.String())
+	})
+	t.Run("CancelarSerie con todos saltea los turnos cerrados", func(t *testing.T) {
+		ahora := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(func() time.Time { return ahora }))
+		serie := makeSerie()
+		mockRepo.On("GetSerie", mock.Anything, "s1").Return(serie, nil)
+		mockRepo.On("GetByID", mock.Anything, serie.Turnos[2].ID).Return(serie.Turnos[2], nil)
+		mockRepo.On("CambiarEstados", mock.Anything, serie.Turnos[1:], mock.MatchedBy(func(eventos []domain.EventoTurno) bool {
+			return len(eventos) == 2 && eventos[0].TurnoID == serie.Turnos[1].ID && eventos[1].Estado == domain.Cancelado
+		})).Return(nil)
+
+		got, err := s.CancelarSerie(context.Background(), serie.Turnos[2].ID, domain.TodaLaSerie,
+			domain.Cancelacion{Origen: domain.CanceladoPorCliente, Motivo: "se muda"})
+		assert.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, domain.Completado, serie.Turnos[0].Estado)
+		assert.Equal(t, domain.Cancelado, serie.Turnos[1].Estado)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "CambiarEstado", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("CancelarSerie no cancela ninguno si uno no se puede cancelar", func(t *testing.T) {
+		// el segundo turno abierto está a menos de un día y las tardías se rechazan
+		ahora := time.Date(2025, 6, 22, 12, 0, 0, 0, time.UTC)
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithPoliticaCancelacion(domain.PoliticaCancelacion{AnticipacionMinima: 48 * time.Hour, RechazarTardias: true}))
+		serie := makeSerie()
+		serie.Turnos[0].Estado = domain.Pendiente
+		serie.Turnos[0].Fecha = time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+		serie.Turnos[1].Fecha = time.Date(2025, 6, 23, 0, 0, 0, 0, time.UTC)
+		mockRepo.On("GetByID", mock.Anything, serie.Turnos[0].ID).Return(serie.Turnos[0], nil)
+		mockRepo.On("GetSerie", mock.Anything, "s1").Return(serie, nil)
+
+		got, err := s.CancelarSerie(context.Background(), serie.Turnos[0].ID, domain.TodaLaSerie,
+			domain.Cancelacion{Origen: domain.CanceladoPorCliente, Motivo: "se muda"})
+		assert.ErrorIs(t, err, domain.ErrCancelacionTardia)
+		assert.ErrorContains(t, err, "turno del 2025-06-23")
+		assert.Nil(t, got)
+		mockRepo.AssertNotCalled(t, "CambiarEstados", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "CambiarEstado", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Si falla el guardado no queda ninguno cancelado", func(t *testing.T) {
+		ahora := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(func() time.Time { return ahora }))
+		serie := makeSerie()
+		mockRepo.On("GetByID", mock.Anything, serie.Turnos[1].ID).Return(serie.Turnos[1], nil)
+		mockRepo.On("GetSerie", mock.Anything, "s1").Return(serie, nil)
+		mockRepo.On("CambiarEstados", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("conexión perdida"))
+
+		got, err := s.CancelarSerie(context.Background(), serie.Turnos[1].ID, domain.TodaLaSerie,
+			domain.Cancelacion{Origen: domain.CanceladoPorNegocio, Motivo: "cierre"})
+		assert.EqualError(t, err, "conexión perdida")
+		assert.Nil(t, got)
+		mockRepo.AssertNotCalled(t, "CambiarEstado", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("ActualizarSerie no pasa la serie a otro cliente", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		serie := makeSerie()
+		elegido := serie.Turnos[1]
+		mockRepo.On("GetByID", mock.Anything, elegido.ID).Return(elegido, nil)
+		mockRepo.On("GetSerie", mock.Anything, "s1").Return(serie, nil)
+
+		cambio := makeTurnoConID(elegido.ID)
+		cambio.Fecha = elegido.Fecha
+		cambio.Cliente.ID = "456"
+		for _, alcance := range []domain.AlcanceSerie{domain.SoloEste, domain.EsteYSiguientes, domain.TodaLaSerie} {
+			_, err := s.ActualizarSerie(context.Background(), cambio, alcance)
+			assert.ErrorIs(t, err, domain.ErrClienteDeSerie)
+		}
+		mockRepo.AssertNotCalled(t, "GuardarVarios", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+	t.Run("Un turno suelto no tiene serie", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		suelto := makeTurno("02")
+		mockRepo.On("GetByID", mock.Anything, suelto.ID).Return(suelto, nil)
+
+		_, err := s.CancelarSerie(context.Background(), suelto.ID, domain.TodaLaSerie,
+			domain.Cancelacion{Origen: domain.CanceladoPorNegocio, Motivo: "cierre"})
+		assert.EqualError(t, err, "el turno no pertenece a una serie")
+	})
+}
+
+func TestTurnoService_ListaEspera(t *testing.T) {
+	ahora := time.Date(2025, 5, 30, 9, 0, 0, 0, time.UTC)
+	vigencia := 2 * time.Hour
+	setup := func(t *testing.T) (turno.TurnoService, *MockTurnoRepository, *MockListaEsperaRepository) {
+		mockRepo := new(MockTurnoRepository)
+		mockEspera := new(MockListaEsperaRepository)
+		s := turno.NewTurnoService(mockRepo, nil,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithListaEspera(mockEspera, vigencia))
+		return s, mockRepo, mockEspera
+	}
+	// makeTurno("01") libera el 1 de junio de 10:30 a 11:00
+	entrada := func(id string, pref *domain.PreferenciaHoraria) *domain.EntradaEspera {
+		base := makeTurno("01")
+		desde, cliente := base.Fecha, base.Cliente
+		cliente.ID = "c-" + id
+		return &domain.EntradaEspera{
+			ID: id, Cliente: cliente,
+			Desde: desde, Hasta: desde.AddDate(0, 0, 7),
+			Preferencia: pref, Duracion: 30 * time.Minute,
+		}
+	}
+	tarde := domain.PreferenciaHoraria(domain.Tarde)
+	cancelacion := domain.Cancelacion{Origen: domain.CanceladoPorNegocio, Motivo: "imprevisto"}
+
+	t.Run("Al cancelar se ofrece el lugar a la primera entrada que sirve", func(t *testing.T) {
+		s, mockRepo, mockEspera := setup(t)
+		cancelado := makeTurno("01")
+		noSirve, primera, segunda := entrada("a", &tarde), entrada("b", nil), entrada("c", nil)
+		mockRepo.On("GetByID", mock.Anything, cancelado.ID).Return(cancelado, nil)
+		mockRepo.On("CambiarEstado", mock.Anything, cancelado, mock.Anything).Return(nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockEspera.On("GetEsperando", mock.Anything, cancelado.Fecha).Return([]*domain.EntradaEspera{noSirve, primera, segunda}, nil)
+		mockEspera.On("GuardarOferta", mock.Anything, mock.Anything, primera).Return(nil)
+
+		_, err := s.Cancelar(context.Background(), cancelado.ID, cancelacion)
+		assert.NoError(t, err)
+		mockEspera.AssertExpectations(t)
+		assert.Equal(t, domain.ConOferta, primera.Estado)
+		assert.Equal(t, ahora.Add(vigencia), primera.Oferta.VenceEn)
+		assert.Equal(t, cancelado.Hora, primera.Oferta.Hora)
+		assert.Equal(t, domain.Esperando, segunda.Estado)
+	})
+	t.Run("Un lugar que ya pasó no se ofrece", func(t *testing.T) {
+		s, mockRepo, mockEspera := setup(t)
+		pasado := makeTurno("01")
+		pasado.Fecha = ahora.AddDate(0, 0, -1)
+		mockRepo.On("GetByID", mock.Anything, pasado.ID).Return(pasado, nil)
+		mockRepo.On("Delete", mock.Anything, pasado.ID).Return(nil)
+
+		assert.NoError(t, s.Delete(context.Background(), pasado.ID))
+		mockEspera.AssertNotCalled(t, "GetEsperando", mock.Anything, mock.Anything)
+	})
+	t.Run("Un lugar dentro de la anticipación mínima no se ofrece", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockEspera := new(MockListaEsperaRepository)
+		// faltan 49 horas y media para el lugar, y se reserva con al menos 72
+		s := turno.NewTurnoService(mockRepo, nil,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithPoliticaReserva(domain.PoliticaReserva{AnticipacionMinima: 72 * time.Hour}),
+			turno.WithListaEspera(mockEspera, vigencia))
+		cancelado := makeTurno("01")
+		mockRepo.On("GetByID", mock.Anything, cancelado.ID).Return(cancelado, nil)
+		mockRepo.On("CambiarEstado", mock.Anything, cancelado, mock.Anything).Return(nil)
+
+		_, err := s.Cancelar(context.Background(), cancelado.ID, cancelacion)
+		assert.NoError(t, err)
+		mockEspera.AssertNotCalled(t, "GetEsperando", mock.Anything, mock.Anything)
+		mockEspera.AssertNotCalled(t, "GuardarOferta", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Aceptar la oferta crea el turno", func(t *testing.T) {
+		s, mockRepo, mockEspera := setup(t)
+		e := entrada("b", nil)
+		e.Estado = domain.ConOferta
+		o := &domain.Oferta{ID: "o1", EntradaID: "b", Fecha: makeTurno("01").Fecha,
+			Hora: domain.TimeOfDay{Hour: 10, Minute: 30}, Duracion: time.Hour, VenceEn: ahora.Add(time.Hour)}
+		mockEspera.On("GetOferta", mock.Anything, "o1").Return(o, nil)
+		mockEspera.On("GetByID", mock.Anything, "b").Return(e, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		// el lugar sigue ofrecido, pero a esta misma entrada
+		mockEspera.On("GetOfertasPendientes", mock.Anything, mock.Anything, mock.Anything, ahora).Return([]*domain.Oferta{o}, nil)
+		var reservado *domain.Turno
+		mockRepo.On("CreateOrUpdate", mock.Anything, mock.AnythingOfType("*domain.Turno")).Return(makeTurno("01"), nil).
+			Run(func(args mock.Arguments) { reservado = args.Get(1).(*domain.Turno) })
+		mockEspera.On("GuardarOferta", mock.Anything, o, e).Return(nil)
+
+		got, err := s.AceptarOferta(context.Background(), "o1")
+		assert.NoError(t, err)
+		assert.Equal(t, "c-b", reservado.Cliente.ID)
+		assert.Equal(t, e.Duracion, reservado.Duracion)
+		assert.Equal(t, domain.OfertaAceptada, o.Estado)
+		assert.Equal(t, got.ID, o.TurnoID)
+		assert.Equal(t, domain.Atendida, e.Estado)
+	})
+	t.Run("Una oferta vencida pasa al siguiente", func(t *testing.T) {
+		s, mockRepo, mockEspera := setup(t)
+		e, siguiente := entrada("b", nil), entrada("c", nil)
+		e.Estado = domain.ConOferta
+		o := &domain.Oferta{ID: "o1", EntradaID: "b", Fecha: makeTurno("01").Fecha,
+			Hora: domain.TimeOfDay{Hour: 10, Minute: 30}, Duracion: 30 * time.Minute, VenceEn: ahora}
+		mockEspera.On("GetOferta", mock.Anything, "o1").Return(o, nil)
+		mockEspera.On("GetByID", mock.Anything, "b").Return(e, nil)
+		mockEspera.On("GuardarOferta", mock.Anything, o, e).Return(nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		// la entrada que dejó vencer la oferta vuelve a esperar, pero no recibe el mismo lugar
+		mockEspera.On("GetEsperando", mock.Anything, o.Fecha).Return([]*domain.EntradaEspera{e, siguiente}, nil)
+		mockEspera.On("GuardarOferta", mock.Anything, mock.Anything, siguiente).Return(nil)
+
+		got, err := s.AceptarOferta(context.Background(), "o1")
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, domain.ErrOfertaVencida)
+		assert.Equal(t, domain.OfertaVencida, o.Estado)
+		assert.Equal(t, domain.Esperando, e.Estado)
+		assert.Equal(t, domain.ConOferta, siguiente.Estado)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+	t.Run("Un lugar ofrecido no se puede reservar ni aparece disponible", func(t *testing.T) {
+		s, mockRepo, mockEspera := setup(t)
+		lugar := makeTurno("01")
+		o := &domain.Oferta{ID: "o1", EntradaID: "b", Fecha: lugar.Fecha, Hora: lugar.Hora,
+			Duracion: lugar.Duracion, VenceEn: ahora.Add(time.Hour)}
+		mockEspera.On("GetOfertasPendientes", mock.Anything, mock.Anything, mock.Anything, ahora).Return([]*domain.Oferta{o}, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+
+		res, err := s.Create(context.Background(), makeTurno("01"))
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, domain.ErrLugarOfrecido)
+		mockRepo.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+
+		libres, err := s.Disponibles(context.Background(), domain.ConsultaDisponibilidad{
+			Desde: lugar.Fecha, Hasta: lugar.Fecha, Duracion: 30 * time.Minute, Intervalo: 30 * time.Minute,
+		})
+		assert.NoError(t, err)
+		assert.Contains(t, horas(libres[0].Horarios), "10:00")
+		assert.NotContains(t, horas(libres[0].Horarios), "10:30")
+		assert.Contains(t, horas(libres[0].Horarios), "11:00")
+	})
+	t.Run("Quien necesita seña no se puede anotar", func(t *testing.T) {
+		mockEspera := new(MockListaEsperaRepository)
+		mockCliente := new(MockClienteService)
+		s := turno.NewTurnoService(new(MockTurnoRepository), mockCliente,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithListaEspera(mockEspera, vigencia))
+		e := entrada("a", nil)
+		conSena := e.Cliente
+		conSena.Restriccion = domain.RequiereSena
+		mockCliente.On("GetByID", mock.Anything, e.Cliente.ID).Return(&conSena, nil)
+
+		res, err := s.AnotarEnEspera(context.Background(), e)
+		assert.Nil(t, res)
+		assert.ErrorIs(t, err, domain.ErrSenaRequerida)
+		mockEspera.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+	t.Run("Al ofrecer se saltea a quien ahora necesita seña", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockEspera := new(MockListaEsperaRepository)
+		mockCliente := new(MockClienteService)
+		s := turno.NewTurnoService(mockRepo, mockCliente,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithListaEspera(mockEspera, vigencia))
+		cancelado := makeTurno("01")
+		conSena, sinSena := entrada("a", nil), entrada("b", nil)
+		restringido := conSena.Cliente
+		restringido.Restriccion = domain.RequiereSena
+		mockRepo.On("GetByID", mock.Anything, cancelado.ID).Return(cancelado, nil)
+		mockRepo.On("CambiarEstado", mock.Anything, cancelado, mock.Anything).Return(nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockCliente.On("GetByID", mock.Anything, conSena.Cliente.ID).Return(&restringido, nil)
+		mockCliente.On("GetByID", mock.Anything, sinSena.Cliente.ID).Return(&sinSena.Cliente, nil)
+		mockEspera.On("GetEsperando", mock.Anything, cancelado.Fecha).Return([]*domain.EntradaEspera{conSena, sinSena}, nil)
+		mockEspera.On("GuardarOferta", mock.Anything, mock.Anything, sinSena).Return(nil)
+
+		_, err := s.Cancelar(context.Background(), cancelado.ID, cancelacion)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Esperando, conSena.Estado)
+		assert.Equal(t, domain.ConOferta, sinSena.Estado)
+	})
+	t.Run("Al reprogramar se ofrece solo lo que quedó libre", func(t *testing.T) {
+		s, mockRepo, mockEspera := setup(t)
+		actual := makeTurno("01") // 10:30 a 11:00, pasa a 10:45 a 11:15
+		corta := entrada("a", nil)
+		corta.Duracion = 15 * time.Minute
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockRepo.On("Reprogramar", mock.Anything, actual, mock.Anything).Return(nil)
+		mockEspera.On("GetOfertasPendientes", mock.Anything, mock.Anything, mock.Anything, ahora).Return([]*domain.Oferta{}, nil)
+		mockEspera.On("GetEsperando", mock.Anything, actual.Fecha).Return([]*domain.EntradaEspera{corta}, nil)
+		mockEspera.On("GuardarOferta", mock.Anything, mock.Anything, corta).Return(nil)
+
+		_, err := s.Reprogramar(context.Background(), actual.ID, actual.Fecha, domain.TimeOfDay{Hour: 10, Minute: 45})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TimeOfDay{Hour: 10, Minute: 30}, corta.Oferta.Hora)
+		assert.Equal(t, 15*time.Minute, corta.Oferta.Duracion)
+		mockEspera.AssertNumberOfCalls(t, "GuardarOferta", 1)
+	})
+	t.Run("Una oferta respondida no se puede aceptar", func(t *testing.T) {
+		s, _, mockEspera := setup(t)
+		mockEspera.On("GetOferta", mock.Anything, "o1").Return(&domain.Oferta{ID: "o1", Estado: domain.OfertaRechazada}, nil)
+
+		_, err := s.AceptarOferta(context.Background(), "o1")
+		assert.ErrorIs(t, err, domain.ErrOfertaCerrada)
+	})
+}
+
+func TestTurnoService_CerrarAgenda(t *testing.T) {
+	lunes, _ := time.Parse("2006/01/02", "2025/06/02")
+	cierre := domain.Cierre{Desde: lunes, Hasta: lunes, Motivo: "enfermedad"}
+	// los martes se atiende de 16:00 a 17:30, todo de tarde
+	semana := []*domain.HorarioLaboral{
+		domain.NewHorarioLaboral(time.Tuesday, []domain.IntervaloHorario{
+			{Desde: domain.TimeOfDay{Hour: 16, Minute: 0}, Hasta: domain.TimeOfDay{Hour: 17, Minute: 30}},
+		}),
+	}
+
+	t.Run("Cancela, avisa y sugiere horarios distintos a cada cliente", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockHorario := new(MockHorarioService)
+		mockBloqueo := new(MockBloqueoService)
+		mockNotificaciones := new(MockNotificacionRepository)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy),
+			turno.WithHorarioService(mockHorario), turno.WithBloqueoService(mockBloqueo),
+			turno.WithNotificaciones(mockNotificaciones))
+
+		a := makeTurno("02")
+		b := makeTurno("02")
+		b.Hora = domain.TimeOfDay{Hour: 16, Minute: 0}
+		b.Cliente = domain.Cliente{ID: "456", Nombre: "Otra", Telefono: "987", PreferenciaHoraria: domain.Tarde}
+		mockRepo.On("GetEntreFechas", mock.Anything, lunes, lunes, domain.EstadosActivos).Return([]*domain.Turno{a, b}, nil)
+		mockHorario.On("GetAll", mock.Anything).Return(semana, nil)
+		mockBloqueo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Bloqueo{}, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		var eventos []domain.EventoTurno
+		mockRepo.On("CerrarAgenda", mock.Anything, mock.Anything, mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) { eventos = args.Get(2).([]domain.EventoTurno) })
+
+		res, err := s.CerrarAgenda(context.Background(), cierre)
+		assert.NoError(t, err)
+		assert.Len(t, res.Bloqueos, 1)
+		bl := res.Bloqueos[0]
+		assert.NotEmpty(t, bl.ID)
+		assert.Equal(t, lunes, bl.Fecha)
+		assert.Equal(t, domain.TimeOfDay{}, bl.Desde)
+		assert.Equal(t, domain.FinDelDia, bl.Hasta) // hasta la medianoche, no 23:59
+		assert.Equal(t, "enfermedad", bl.Motivo)
+		assert.Len(t, res.Afectados, 2)
+		assert.Len(t, eventos, 2)
+		for i, af := range res.Afectados {
+			assert.Equal(t, domain.Cancelado, af.Turno.Estado)
+			assert.Equal(t, domain.CanceladoPorNegocio, af.Turno.Cancelacion.Origen)
+			assert.Equal(t, af.Turno.ID, eventos[i].TurnoID)
+			assert.Len(t, af.Sugerencias, 3)
+		}
+		// el primero se queda con el martes siguiente y el segundo con el otro
+		primero, segundo := res.Afectados[0], res.Afectados[1]
+		assert.Equal(t, lunes.AddDate(0, 0, 1), primero.Sugerencias[0].Fecha)
+		assert.Equal(t, []domain.TimeOfDay{{Hour: 16}, {Hour: 16, Minute: 30}, {Hour: 17}},
+			[]domain.TimeOfDay{primero.Sugerencias[0].Hora, primero.Sugerencias[1].Hora, primero.Sugerencias[2].Hora})
+		assert.Equal(t, lunes.AddDate(0, 0, 8), segundo.Sugerencias[0].Fecha)
+		assert.Equal(t, "123456789", primero.Notificacion.Telefono)
+		assert.Equal(t, "Hola Otra, tu turno del 02/06 a las 16:00 se canceló: enfermedad. "+
+			"Te podemos ofrecer: 10/06 a las 16:00, 10/06 a las 16:30, 10/06 a las 17:00.", segundo.Notificacion.Mensaje)
+		mockRepo.AssertExpectations(t)
+		// los avisos se guardan con el resto del cierre, no por separado
+		mockNotificaciones.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "CambiarEstado", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Los días ya bloqueados enteros no se vuelven a bloquear", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockBloqueo := new(MockBloqueoService)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithBloqueoService(mockBloqueo))
+		martes := lunes.AddDate(0, 0, 1)
+		dosDias := domain.Cierre{Desde: lunes, Hasta: martes, Motivo: "enfermedad"}
+		// el lunes ya lo cubren dos bloqueos; el martes solo a la mañana
+		mediodia := domain.TimeOfDay{Hour: 12}
+		existentes := []*domain.Bloqueo{
+			domain.NewBloqueo("m", lunes, domain.TimeOfDay{}, mediodia, "x", false, time.Time{}),
+			domain.NewBloqueo("t", lunes, mediodia, domain.FinDelDia, "x", false, time.Time{}),
+			domain.NewBloqueo("m2", martes, domain.TimeOfDay{}, mediodia, "x", false, time.Time{}),
+		}
+		mockRepo.On("GetEntreFechas", mock.Anything, lunes, martes, domain.EstadosActivos).Return([]*domain.Turno{}, nil)
+		mockBloqueo.On("GetEnRango", mock.Anything, lunes, martes).Return(existentes, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+		mockRepo.On("CerrarAgenda", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		res, err := s.CerrarAgenda(context.Background(), dosDias)
+		assert.NoError(t, err)
+		assert.Len(t, res.Bloqueos, 1)
+		assert.Equal(t, martes, res.Bloqueos[0].Fecha)
+	})
+	t.Run("Si se reservó otro turno en el medio no guarda nada", func(t *testing.T) {
+		mockRepo := new(MockTurnoRepository)
+		mockBloqueo := new(MockBloqueoService)
+		s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy), turno.WithBloqueoService(mockBloqueo))
+		a, nuevo := makeTurno("02"), makeTurno("02")
+		nuevo.Hora = domain.TimeOfDay{Hour: 15}
+		mockRepo.On("GetEntreFechas", mock.Anything, lunes, lunes, domain.EstadosActivos).Return([]*domain.Turno{a}, nil)
+		mockBloqueo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Bloqueo{}, nil)
+		// lo que ve el repositorio con la agenda bloqueada, y lo que ven las sugerencias
+		mockRepo.On("GetEnRango", mock.Anything, domain.InicioDelDia(lunes, time.UTC), domain.InicioDelDia(lunes.AddDate(0, 0, 1), time.UTC)).
+			Return([]*domain.Turno{a, nuevo}, nil)
+		mockRepo.On("GetEnRango", mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Turno{}, nil)
+
+		res, err := s.CerrarAgenda(context.Background(), cierre)
+		assert.Nil(t, res)
+		var conflicto *domain.ConflictoTurnoError
+		assert.ErrorAs(t, err, &conflicto)
+		assert.Equal(t, []string{nuevo.ID}, conflicto.IDs)
+		mockRepo.AssertNotCalled(t, "CerrarAgenda", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Sin bloqueos de agenda no cierra", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		_, err := s.CerrarAgenda(context.Background(), cierre)
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "CambiarEstado", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("Cierre inválido", func(t *testing.T) {
+		s, _ := setupTurnoServiceWithMock(t)
+		for _, c := range []domain.Cierre{
+			{Desde: lunes, Hasta: lunes.AddDate(0, 0, -1), Motivo: "enfermedad"},
+			{Desde: lunes, Hasta: lunes.AddDate(0, 0, domain.DiasMaximosCierre), Motivo: "vacaciones"},
+			{Desde: lunes, Hasta: lunes},
+		} {
+			_, err := s.CerrarAgenda(context.Background(), c)
+			assert.Error(t, err)
+		}
+	})
+}
+
 func TestTurnoService_Delete(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -186,7 +2001,7 @@ func TestTurnoService_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(MockTurnoRepository)
-			s := turno.NewTurnoService(mockRepo, nil)
+			s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy))
 			if tt.wantErr {
 				mockRepo.On("Delete", mock.Anything, "123").Return(assert.AnError)
 			} else {
@@ -220,7 +2035,7 @@ func TestTurnoService_GetByFecha(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s, mockRepo := setupTurnoServiceWithMock(t)
 			fechaprueba, _ := time.Parse("2006/01/02", "2025/08/15")
-			mockRepo.On("GetByFecha", mock.Anything, fechaprueba).Return(tt.mockData, tt.mockErr)
+			mockRepo.On("GetByFecha", mock.Anything, fechaprueba, mock.Anything).Return(tt.mockData, tt.mockErr)
 
 			got, err := s.GetByFecha(context.Background(), fechaprueba)
 
@@ -262,7 +2077,7 @@ func TestTurnoService_GetAll(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s, mockRepo := setupTurnoServiceWithMock(t)
-			mockRepo.On("GetAll", mock.Anything).Return(tt.mockData, tt.mockErr)
+			mockRepo.On("GetAll", mock.Anything, mock.Anything).Return(tt.mockData, tt.mockErr)
 
 			got, err := s.GetAll(context.Background())
 
@@ -282,9 +2097,10 @@ func TestTurnoService_GetAll(t *testing.T) {
 func makeTurno(dia string) *domain.Turno {
 	fecha, _ := time.Parse("2006/01/02", fmt.Sprintf("2025/06/%s", dia))
 	return &domain.Turno{
-		ID:    uuid.NewString(),
-		Fecha: fecha,
-		Hora:  domain.TimeOfDay{Hour: 10, Minute: 30},
+		ID:       uuid.NewString(),
+		Fecha:    fecha,
+		Hora:     domain.TimeOfDay{Hour: 10, Minute: 30},
+		Duracion: domain.DuracionPorDefecto,
 		Cliente: domain.Cliente{
 			ID:                 "123",
 			Nombre:             "Cliente Test",
@@ -294,9 +2110,40 @@ func makeTurno(dia string) *domain.Turno {
 	}
 }
 
+func makeTurnoConID(id string) *domain.Turno {
+	t := makeTurno("01")
+	t.ID = id
+	return t
+}
+
+// ventana son los argumentos con que se consultan los turnos que pueden chocar
+// con t sin margen general: su horario ampliado en domain.MargenMaximo.
+func ventana(t *domain.Turno) []interface{} {
+	return []interface{}{mock.Anything, t.Inicio().Add(-domain.MargenMaximo), t.Fin().Add(domain.MargenMaximo)}
+}
+
+// delDia son los argumentos con que Disponibles busca los turnos que pueden
+// ocupar fecha.
+func delDia(fecha time.Time) []interface{} {
+	return []interface{}{mock.Anything, fecha.Add(-domain.MargenMaximo), fecha.AddDate(0, 0, 1).Add(domain.MargenMaximo)}
+}
+
+func horas(hs []domain.TimeOfDay) []string {
+	res := make([]string, 0, len(hs))
+	for _, h := range hs {
+		res = append(res, h.String())
+	}
+	return res
+}
+
+// hoy es la hora fija que ven los servicios de prueba, antes de los turnos de makeTurno.
+func hoy() time.Time {
+	return time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+}
+
 func setupTurnoServiceWithMock(t *testing.T) (turno.TurnoService, *MockTurnoRepository) {
 	mockRepo := new(MockTurnoRepository)
-	s := turno.NewTurnoService(mockRepo, nil)
+	s := turno.NewTurnoService(mockRepo, nil, turno.WithReloj(hoy))
 	return s, mockRepo
 }
 
@@ -334,3 +2181,119 @@ Desacople de dependencias: nunca testear con una base de datos real, siempre con
 
 Resultado final: la estructura del test se convierte en una representación declarativa de las decisiones y rutas lógicas del servicio. Cada ruta esperada o error anticipado debe estar cubierto.
 */
+
+func TestTurnoService_Buscar(t *testing.T) {
+	desde, _ := time.Parse(time.DateOnly, "2025-06-01")
+	hasta, _ := time.Parse(time.DateOnly, "2025-06-30")
+
+	t.Run("Pasa el filtro al repositorio", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		f := domain.FiltroTurnos{Desde: desde, Hasta: hasta, ClienteID: "123", Estados: []domain.EstadoTurno{domain.Confirmado}}
+		turnos := []*domain.Turno{makeTurno("02"), makeTurno("09")}
+		mockRepo.On("Buscar", mock.Anything, f).Return(turnos, nil)
+
+		got, err := s.Buscar(context.Background(), f)
+		assert.NoError(t, err)
+		assert.Equal(t, turnos, got)
+		mockRepo.AssertExpectations(t)
+	})
+	t.Run("Rango invertido", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+
+		got, err := s.Buscar(context.Background(), domain.FiltroTurnos{Desde: hasta, Hasta: desde})
+		assert.Nil(t, got)
+		assert.EqualError(t, err, "rango de fechas inválido")
+		mockRepo.AssertNotCalled(t, "Buscar", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTurnoService_HistorialCliente(t *testing.T) {
+	visita := func(id, dia string, estado domain.EstadoTurno, sena float64, precio float64) *domain.Turno {
+		tr := makeTurnoConID(id)
+		tr.Fecha, _ = time.Parse("2006/01/02", "2025/06/"+dia)
+		tr.Estado = estado
+		tr.Sena = sena
+		if precio > 0 {
+			tr.Servicios = []domain.Servicio{{ID: "corte", Nombre: "Corte", Duracion: 30 * time.Minute, Precio: precio}}
+		}
+		return tr
+	}
+
+	t.Run("Resume las visitas completadas", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		turnos := []*domain.Turno{
+			visita("1", "01", domain.Completado, 0, 8000),
+			visita("2", "05", domain.Cancelado, 2000, 8000), // la seña quedó
+			visita("3", "11", domain.Completado, 2000, 8000),
+			visita("4", "21", domain.Completado, 0, 9000),
+			visita("5", "28", domain.Pendiente, 3000, 9000),
+		}
+		mockRepo.On("Buscar", mock.Anything, domain.FiltroTurnos{ClienteID: "123"}).Return(turnos, nil)
+
+		h, err := s.HistorialCliente(context.Background(), "123")
+		assert.NoError(t, err)
+		assert.Equal(t, turnos, h.Turnos)
+		assert.Equal(t, 3, h.Visitas)
+		assert.Equal(t, turnos[0].Fecha, h.PrimeraVisita)
+		assert.Equal(t, turnos[3].Fecha, h.UltimaVisita)
+		assert.InDelta(t, 10.0, h.DiasEntreVisitas, 0.001)
+		// 8000 + 2000 de seña + 8000 (incluye su seña) + 9000 + 3000 de seña
+		assert.InDelta(t, 30000.0, h.TotalPagado, 0.001)
+	})
+	t.Run("Cliente sin visitas", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		mockRepo.On("Buscar", mock.Anything, domain.FiltroTurnos{ClienteID: "123"}).Return([]*domain.Turno{}, nil)
+
+		h, err := s.HistorialCliente(context.Background(), "123")
+		assert.NoError(t, err)
+		assert.Zero(t, h.Visitas)
+		assert.True(t, h.PrimeraVisita.IsZero())
+		assert.Zero(t, h.DiasEntreVisitas)
+	})
+	t.Run("Error del repositorio", func(t *testing.T) {
+		s, mockRepo := setupTurnoServiceWithMock(t)
+		mockRepo.On("Buscar", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		h, err := s.HistorialCliente(context.Background(), "123")
+		assert.Nil(t, h)
+		assert.Equal(t, assert.AnError, err)
+	})
+}
+
+func TestTurnoService_ZonaHoraria(t *testing.T) {
+	zona, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	assert.NoError(t, err)
+
+	actual := makeTurno("02")
+	actual.Zona = zona // 10:30 en Buenos Aires
+	assert.Equal(t, time.Date(2025, 6, 2, 13, 30, 0, 0, time.UTC), actual.Inicio().UTC())
+
+	fecha, hora := domain.Separar(time.Date(2025, 6, 3, 1, 0, 0, 0, time.UTC), zona)
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), fecha)
+	assert.Equal(t, domain.TimeOfDay{Hour: 22, Minute: 0}, hora)
+
+	t.Run("La anticipación se mide en la hora del negocio", func(t *testing.T) {
+		// a las 23:00 UTC del día anterior faltan 14 horas y media, no 11 y media
+		ahora := time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)
+		mockRepo := new(MockTurnoRepository)
+		s := turno.NewTurnoService(mockRepo, nil,
+			turno.WithReloj(func() time.Time { return ahora }),
+			turno.WithPoliticaCancelacion(domain.PoliticaCancelacion{AnticipacionMinima: 12 * time.Hour}))
+		mockRepo.On("GetByID", mock.Anything, actual.ID).Return(actual, nil)
+		mockRepo.On("CambiarEstado", mock.Anything, actual, mock.Anything).Return(nil)
+
+		res, err := s.Cancelar(context.Background(), actual.ID, domain.Cancelacion{Origen: domain.CanceladoPorCliente, Motivo: "viaje"})
+		assert.NoError(t, err)
+		assert.False(t, res.Cancelacion.Tardia)
+	})
+
+	t.Run("La fecha y la hora pedidas son del negocio", func(t *testing.T) {
+		mockCliente := new(MockClienteService)
+		s := turno.NewTurnoService(new(MockTurnoRepository), mockCliente, turno.WithZonaHoraria(zona))
+		mockCliente.On("GetByID", mock.Anything, "123").Return(&domain.Cliente{ID: "123"}, nil)
+
+		res, err := s.ToDomain(context.Background(), &dto.TurnoRequest{ID: "1", Fecha: "2025/06/02", Hora: "10:30", ClienteID: "123"})
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2025, 6, 2, 13, 30, 0, 0, time.UTC), res.Inicio().UTC())
+	})
+}
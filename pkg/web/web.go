@@ -26,3 +26,16 @@ func Error(w http.ResponseWriter, status int, message string) {
 
 	json.NewEncoder(w).Encode(resp)
 }
+
+// ErrorWithDetails envía un error como Error, agregando datos extra en el campo "details".
+func ErrorWithDetails(w http.ResponseWriter, status int, message string, details any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]any{
+		"error":   message,
+		"details": details,
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}